@@ -1,7 +1,9 @@
 package account_sgx
 
 import (
+	"encoding/json"
 	"fmt"
+	"github.com/superconsensus/matrix-sdk-go/v2/account"
 	"github.com/superconsensus/matrix-sdk-go/v2/common"
 	"log"
 	"regexp"
@@ -94,3 +96,57 @@ func (a *AccountSgx) GetContractAccount() string {
 func (a *AccountSgx) HasContractAccount() bool {
 	return a.contractAccount != ""
 }
+
+// 签名信息，与 sgx 服务 sign 接口返回的数据格式一致
+type signInfo struct {
+	PublicKey string `json:"public_key"`
+	Sign      []byte `json:"sign"`
+}
+
+// SignMessage sign an arbitrary message in sgx, the digest is the same as account.SignMessage,
+// so the signature can also be verified by account.VerifyMessage.
+// Returns JSON encoded public key and signature.
+func (a *AccountSgx) SignMessage(msg []byte) (string, []byte, error) {
+	signArgs := map[string]interface{}{
+		"address": a.Address,
+		"msg":     account.MessageHash(msg),
+	}
+	result, err := a.APISgx.Sign(SignMethod, signArgs)
+	if err != nil {
+		return "", nil, err
+	}
+	if result.Code != 200 {
+		return "", nil, fmt.Errorf("sign message error: %s", result.Msg)
+	}
+
+	info := &signInfo{}
+	if err := json.Unmarshal(result.Data, info); err != nil {
+		return "", nil, err
+	}
+	return info.PublicKey, info.Sign, nil
+}
+
+// VerifyMessage verify message signature of this account in sgx, returns nil if the signature is valid.
+func (a *AccountSgx) VerifyMessage(msg []byte, publicKey string, sign []byte) error {
+	signData, err := json.Marshal(&signInfo{PublicKey: publicKey, Sign: sign})
+	if err != nil {
+		return err
+	}
+	verifyArgs := map[string]interface{}{
+		"address": a.Address,
+		"sign":    signData,
+		"msg":     account.MessageHash(msg),
+	}
+	result, err := a.APISgx.Verify(VerifyMethod, verifyArgs)
+	if err != nil {
+		return err
+	}
+	if result.Code != 200 {
+		return fmt.Errorf("verify message error: %s", result.Msg)
+	}
+
+	if ok, _ := strconv.ParseBool(string(result.Data)); !ok {
+		return common.ErrInvalidSignature
+	}
+	return nil
+}
@@ -1,6 +1,87 @@
 package account_sgx
 
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/superconsensus/matrix-sdk-go/v2/account"
+	"github.com/superconsensus/matrix-sdk-go/v2/common"
+	"github.com/superconsensus/matrix-sdk-go/v2/crypto"
+)
+
 //// 测试创建
 //func TestCreateAccountSgx(t *testing.T) {
 //
 //}
+
+// mockApiClient sign and verify by local account instead of sgx service.
+type mockApiClient struct {
+	acc *account.Account
+}
+
+func (m *mockApiClient) Ping(method string, args map[string]interface{}) (*Response, error) {
+	return &Response{Code: 200}, nil
+}
+
+func (m *mockApiClient) Create(method string, args map[string]interface{}) (*Response, error) {
+	return &Response{Code: 200, Data: []byte(m.acc.Address)}, nil
+}
+
+func (m *mockApiClient) Sign(method string, args map[string]interface{}) (*Response, error) {
+	cli := crypto.GetCryptoClient()
+	privateKey, err := cli.GetEcdsaPrivateKeyFromJsonStr(m.acc.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	sign, err := cli.SignECDSA(privateKey, args["msg"].([]byte))
+	if err != nil {
+		return nil, err
+	}
+	data, _ := json.Marshal(&signInfo{PublicKey: m.acc.PublicKey, Sign: sign})
+	return &Response{Code: 200, Data: data}, nil
+}
+
+func (m *mockApiClient) Verify(method string, args map[string]interface{}) (*Response, error) {
+	info := &signInfo{}
+	if err := json.Unmarshal(args["sign"].([]byte), info); err != nil {
+		return nil, err
+	}
+	cli := crypto.GetCryptoClient()
+	publicKey, err := cli.GetEcdsaPublicKeyFromJsonStr(info.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	ok, _ := cli.VerifyECDSA(publicKey, info.Sign, args["msg"].([]byte))
+	return &Response{Code: 200, Data: []byte(strconv.FormatBool(ok))}, nil
+}
+
+func (m *mockApiClient) IsExist(method string, args map[string]interface{}) (*Response, error) {
+	return &Response{Code: 200, Data: []byte(strconv.FormatBool(args["address"] == m.acc.Address))}, nil
+}
+
+func TestSignMessage(t *testing.T) {
+	acc, _ := account.CreateAccount(1, 1)
+	accSgx := &AccountSgx{
+		Address: acc.Address,
+		APISgx:  &mockApiClient{acc: acc},
+	}
+
+	msg := []byte("login challenge")
+	publicKey, sign, err := accSgx.SignMessage(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := accSgx.VerifyMessage(msg, publicKey, sign); err != nil {
+		t.Error("AccountSgx VerifyMessage assert failed", err)
+	}
+	if err := accSgx.VerifyMessage([]byte("other"), publicKey, sign); !errors.Is(err, common.ErrInvalidSignature) {
+		t.Error("AccountSgx VerifyMessage other message assert failed", err)
+	}
+
+	// signature from sgx is the same as local account.
+	if err := account.VerifyMessage(acc.Address, publicKey, msg, sign); err != nil {
+		t.Error("account VerifyMessage sgx signature assert failed", err)
+	}
+}
@@ -9,7 +9,8 @@ import (
 	"io/ioutil"
 	"log"
 	"path/filepath"
-	"regexp"
+
+	"github.com/pkg/errors"
 
 	"github.com/superconsensus/matrix-sdk-go/v2/common"
 	"github.com/superconsensus/matrix-sdk-go/v2/crypto"
@@ -155,11 +156,39 @@ func GetAccountFromFile(path, passwd string) (*Account, error) {
 	return account, err
 }
 
+// NewWatchOnlyAccount create an account without private key, it can be used as transaction initiator
+// with xuper.WithNotPost, the transaction is not signed and should be signed by HSM or other service later.
+//
+// Parameters:
+//   - `address`：  地址。
+//   - `publicKey`：JSON 格式的公钥，必须与地址匹配。
+func NewWatchOnlyAccount(address, publicKey string) (*Account, error) {
+	cryptoClient := crypto.GetCryptoClient()
+	ecdsaPublicKey, err := cryptoClient.GetEcdsaPublicKeyFromJsonStr(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	if ok, _ := cryptoClient.VerifyAddressUsingPublicKey(address, ecdsaPublicKey); !ok {
+		return nil, errors.Wrap(common.ErrInvalidAccount, "address and public key not match")
+	}
+
+	account := &Account{
+		Address:   address,
+		PublicKey: publicKey,
+	}
+	return account, nil
+}
+
+// IsWatchOnly returns true if this account has no private key.
+func (a *Account) IsWatchOnly() bool {
+	return a.PrivateKey == ""
+}
+
 // SetContractAccount set contract account.
 // If you set contract account, this account represents the contract account.
 // In some scenarios, must set contract account, such as deploy contract.
 func (a *Account) SetContractAccount(contractAccount string) error {
-	if ok, _ := regexp.MatchString(`^XC\d{16}@*`, contractAccount); !ok {
+	if addr, err := ParseAddress(contractAccount); err != nil || !addr.IsContractAccount() {
 		return common.ErrInvalidContractAccount
 	}
 
@@ -264,3 +264,26 @@ func TestSetContractAccount(t *testing.T) {
 		t.Error("account authRequire assert failed")
 	}
 }
+
+func TestNewWatchOnlyAccount(t *testing.T) {
+	acc, _ := CreateAccount(1, 1)
+	other, _ := CreateAccount(1, 1)
+
+	watchAcc, err := NewWatchOnlyAccount(acc.Address, acc.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !watchAcc.IsWatchOnly() || acc.IsWatchOnly() {
+		t.Error("IsWatchOnly assert failed")
+	}
+
+	_, err = NewWatchOnlyAccount(other.Address, acc.PublicKey)
+	if !errors.Is(err, common.ErrInvalidAccount) {
+		t.Error("NewWatchOnlyAccount address not match assert failed", err)
+	}
+
+	_, err = NewWatchOnlyAccount(acc.Address, "invalid")
+	if err == nil {
+		t.Error("NewWatchOnlyAccount invalid public key assert failed")
+	}
+}
@@ -0,0 +1,121 @@
+package account
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/pkg/errors"
+	cryptoConfig "github.com/xuperchain/crypto/core/config"
+	gmHash "github.com/xuperchain/crypto/gm/hash"
+
+	"github.com/superconsensus/matrix-sdk-go/v2/common"
+)
+
+const (
+	// AK address is base58(version + ripemd160 hash + checksum).
+	akAddressSize  = 25
+	akChecksumSize = 4
+)
+
+// Address parsed xchain address.
+type Address struct {
+	// Raw the address string to parse.
+	Raw string
+
+	// Kind XchainAddrType, ContractAccountType or ContractNameType.
+	Kind string
+
+	// Bcname chain name suffix of contract account, such as xuper in XC1111111111111111@xuper, empty if not set.
+	Bcname string
+
+	// Cryptography crypto suite flag of AK address, cryptoConfig.Nist for xchain, cryptoConfig.Gm for gm.
+	Cryptography uint8
+
+	// ChecksumValid base58 checksum verify result of AK address, always true for other kinds which have no checksum.
+	ChecksumValid bool
+}
+
+// ParseAddress parse s as AK address, contract account (XC + 16 numbers, with optional @bcname) or contract name.
+// Returns error if s matches none of them, an AK address with wrong checksum is returned with ChecksumValid false.
+func ParseAddress(s string) (*Address, error) {
+	if s == "" {
+		return nil, errors.Wrap(common.ErrInvalidAddress, "empty address")
+	}
+
+	if strings.HasPrefix(s, accountPrefix) && isAccount(s) == 1 {
+		parts := strings.SplitN(s, accountBcnameSep, 2)
+		addr := &Address{
+			Raw:           s,
+			Kind:          ContractAccountType,
+			ChecksumValid: true,
+		}
+		if len(parts) == 2 {
+			if parts[1] == "" || strings.Contains(parts[1], accountBcnameSep) {
+				return nil, errors.Wrapf(common.ErrInvalidAddress, "invalid contract account %s", s)
+			}
+			addr.Bcname = parts[1]
+		}
+		return addr, nil
+	}
+
+	if raw := base58.Decode(s); len(raw) == akAddressSize {
+		version := raw[0]
+		content, checksum := raw[:akAddressSize-akChecksumSize], raw[akAddressSize-akChecksumSize:]
+
+		var expect []byte
+		if version == cryptoConfig.Gm {
+			expect = gmHash.HashUsingSM3(content)
+		} else {
+			expect = DoubleSha256(content)
+		}
+		return &Address{
+			Raw:           s,
+			Kind:          XchainAddrType,
+			Cryptography:  version,
+			ChecksumValid: bytes.Equal(expect[:akChecksumSize], checksum),
+		}, nil
+	}
+
+	if err := validContractName(s); err == nil {
+		return &Address{
+			Raw:           s,
+			Kind:          ContractNameType,
+			ChecksumValid: true,
+		}, nil
+	}
+
+	return nil, errors.Wrapf(common.ErrInvalidAddress, "unknown address format %s", s)
+}
+
+// ValidateAddress returns nil if s is a valid AK address, contract account or contract name.
+func ValidateAddress(s string) error {
+	addr, err := ParseAddress(s)
+	if err != nil {
+		return err
+	}
+	if !addr.ChecksumValid {
+		return errors.Wrapf(common.ErrInvalidAddress, "address %s checksum mismatch", s)
+	}
+	return nil
+}
+
+// IsAK returns true if this is an AK address.
+func (a *Address) IsAK() bool {
+	return a.Kind == XchainAddrType
+}
+
+// IsContractAccount returns true if this is a contract account.
+func (a *Address) IsContractAccount() bool {
+	return a.Kind == ContractAccountType
+}
+
+// IsContractName returns true if this is a contract name.
+func (a *Address) IsContractName() bool {
+	return a.Kind == ContractNameType
+}
+
+// String returns the raw address.
+func (a *Address) String() string {
+	return a.Raw
+}
@@ -0,0 +1,63 @@
+package account
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/superconsensus/matrix-sdk-go/v2/common"
+	"github.com/superconsensus/matrix-sdk-go/v2/common/config"
+)
+
+func TestParseAddress(t *testing.T) {
+	cfg := config.GetInstance()
+	cfg.SetGMCrypto()
+	gmAcc, _ := CreateAccount(1, 1)
+	cfg.SetXchainCrypto()
+	acc, _ := CreateAccount(1, 1)
+
+	// change the last character to make a typo.
+	typo := acc.Address[:len(acc.Address)-1] + "1"
+	if typo == acc.Address {
+		typo = acc.Address[:len(acc.Address)-1] + "2"
+	}
+
+	cases := []struct {
+		addr          string
+		kind          string
+		bcname        string
+		cryptography  uint8
+		checksumValid bool
+		hasErr        bool
+	}{
+		{addr: acc.Address, kind: XchainAddrType, cryptography: 1, checksumValid: true},
+		{addr: gmAcc.Address, kind: XchainAddrType, cryptography: 2, checksumValid: true},
+		{addr: typo, kind: XchainAddrType, cryptography: 1},
+		{addr: "XC1111111111111111@xuper", kind: ContractAccountType, bcname: "xuper", checksumValid: true},
+		{addr: "XC1111111111111111", kind: ContractAccountType, checksumValid: true},
+		{addr: "counter", kind: ContractNameType, checksumValid: true},
+		{addr: "XC111111111111111@xuper", hasErr: true},
+		{addr: "XC1111111111111111@", hasErr: true},
+		{addr: "a", hasErr: true},
+		{addr: "", hasErr: true},
+	}
+
+	for _, c := range cases {
+		addr, err := ParseAddress(c.addr)
+		if c.hasErr {
+			if !errors.Is(err, common.ErrInvalidAddress) {
+				t.Errorf("ParseAddress %s expect error, got %v", c.addr, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseAddress %s err: %v", c.addr, err)
+			continue
+		}
+		if addr.Kind != c.kind || addr.Bcname != c.bcname || addr.Cryptography != c.cryptography || addr.ChecksumValid != c.checksumValid {
+			t.Errorf("ParseAddress %s assert failed: %+v", c.addr, addr)
+		}
+		if (ValidateAddress(c.addr) == nil) != c.checksumValid {
+			t.Errorf("ValidateAddress %s assert failed", c.addr)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package account
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/xuperchain/crypto/client/service/base"
+	cryptoConfig "github.com/xuperchain/crypto/core/config"
+	"github.com/xuperchain/crypto/core/hdwallet/keychain"
+	gmKeychain "github.com/xuperchain/crypto/gm/hdwallet/keychain"
+
+	"github.com/superconsensus/matrix-sdk-go/v2/crypto"
+)
+
+const (
+	// HardenedKeyStart index of the first hardened child key, index in path like 44' means 44+HardenedKeyStart.
+	HardenedKeyStart uint32 = 0x80000000
+
+	// BIP44Purpose BIP44 purpose field.
+	BIP44Purpose uint32 = 44
+)
+
+// HDKey BIP32 hierarchical deterministic extended key, can be private or public.
+//
+// Private key can derive both normal and hardened child keys,
+// public key (see Neuter) can only derive normal child keys and the derived accounts have no private key.
+type HDKey struct {
+	// JSON encoded extended key, same format as crypto client hd api.
+	key          string
+	isPrivate    bool
+	cryptography uint8
+}
+
+// hdCryptoClient crypto client which can format a public key without private key, both xchain and gm clients implement it.
+type hdCryptoClient interface {
+	base.CryptoClient
+	GetEcdsaPublicKeyJsonFormatStrFromPublicKey(k *ecdsa.PublicKey) (string, error)
+}
+
+// extendedKeyHeader fields of extended key JSON which are the same for xchain and gm.
+type extendedKeyHeader struct {
+	Cryptography uint8
+	IsPrivate    bool
+}
+
+// NewMasterKey generate master key from mnemonic, the crypto suite (xchain or gm) is the one in config.
+//
+// Parameters:
+//   - `mnemonic`： 助记词。
+//   - `language`： 1中文，2英文。
+func NewMasterKey(mnemonic string, language int) (*HDKey, error) {
+	masterKey, err := crypto.GetCryptoClient().GenerateMasterKeyByMnemonic(mnemonic, language)
+	if err != nil {
+		return nil, err
+	}
+	return NewHDKeyFromString(masterKey)
+}
+
+// NewHDKeyFromString load extended key from the string returned by HDKey.String.
+func NewHDKeyFromString(key string) (*HDKey, error) {
+	header := &extendedKeyHeader{}
+	if err := json.Unmarshal([]byte(key), header); err != nil {
+		return nil, errors.Wrap(err, "invalid extended key")
+	}
+	if header.Cryptography != cryptoConfig.Nist && header.Cryptography != cryptoConfig.Gm {
+		return nil, fmt.Errorf("unsupported extended key cryptography: %d", header.Cryptography)
+	}
+
+	return &HDKey{
+		key:          key,
+		isPrivate:    header.IsPrivate,
+		cryptography: header.Cryptography,
+	}, nil
+}
+
+// DeriveAccount derive child account from mnemonic by path, such as m/44'/0'/0'/0/1.
+//
+// Parameters:
+//   - `mnemonic`： 助记词。
+//   - `path`：     推导路径，' 或 h 表示 hardened。
+//   - `language`： 1中文，2英文。
+func DeriveAccount(mnemonic, path string, language int) (*Account, error) {
+	masterKey, err := NewMasterKey(mnemonic, language)
+	if err != nil {
+		return nil, err
+	}
+
+	childKey, err := masterKey.Derive(path)
+	if err != nil {
+		return nil, err
+	}
+	return childKey.Account()
+}
+
+// BIP44Path returns BIP44 path: m/44'/coinType'/account'/change/index.
+func BIP44Path(coinType, account, change, index uint32) string {
+	return fmt.Sprintf("m/%d'/%d'/%d'/%d/%d", BIP44Purpose, coinType, account, change, index)
+}
+
+// ParseDerivationPath parse path to child indexes, hardened indexes have HardenedKeyStart added.
+// The path can start with "m/" (from master key) or be relative, such as "0/1".
+func ParseDerivationPath(path string) ([]uint32, error) {
+	path = strings.TrimSpace(path)
+	if path == "m" || path == "" {
+		return []uint32{}, nil
+	}
+	path = strings.TrimPrefix(path, "m/")
+
+	parts := strings.Split(path, "/")
+	indexes := make([]uint32, 0, len(parts))
+	for _, part := range parts {
+		hardened := false
+		if strings.HasSuffix(part, "'") || strings.HasSuffix(part, "h") || strings.HasSuffix(part, "H") {
+			hardened = true
+			part = part[:len(part)-1]
+		}
+
+		index, err := strconv.ParseUint(part, 10, 32)
+		if err != nil || uint32(index) >= HardenedKeyStart {
+			return nil, fmt.Errorf("invalid derivation path %s", path)
+		}
+		if hardened {
+			index += uint64(HardenedKeyStart)
+		}
+		indexes = append(indexes, uint32(index))
+	}
+	return indexes, nil
+}
+
+// String returns JSON encoded extended key, keep it secret if it is a private key.
+func (k *HDKey) String() string {
+	return k.key
+}
+
+// IsPrivate returns true if this is an extended private key.
+func (k *HDKey) IsPrivate() bool {
+	return k.isPrivate
+}
+
+// Child derive child key by index, index >= HardenedKeyStart means hardened child.
+func (k *HDKey) Child(index uint32) (*HDKey, error) {
+	if !k.isPrivate && index >= HardenedKeyStart {
+		return nil, errors.New("can not derive hardened child key from public key")
+	}
+
+	childKey, err := k.cryptoClient().GenerateChildKey(k.key, index)
+	if err != nil {
+		return nil, err
+	}
+	return NewHDKeyFromString(childKey)
+}
+
+// Derive derive child key by path relative to this key, such as 0/1 or m/44'/0'/0'/0/1.
+func (k *HDKey) Derive(path string) (*HDKey, error) {
+	indexes, err := ParseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key := k
+	for _, index := range indexes {
+		key, err = key.Child(index)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return key, nil
+}
+
+// Neuter returns the extended public key, it can derive normal child public keys only.
+func (k *HDKey) Neuter() (*HDKey, error) {
+	if !k.isPrivate {
+		return k, nil
+	}
+
+	pubKey, err := k.cryptoClient().ConvertPrvKeyToPubKey(k.key)
+	if err != nil {
+		return nil, err
+	}
+	return NewHDKeyFromString(pubKey)
+}
+
+// Account returns the account of this key, the account has no private key if this is a public key.
+func (k *HDKey) Account() (*Account, error) {
+	if k.isPrivate {
+		privateKey, err := k.ecdsaPrivateKey()
+		if err != nil {
+			return nil, err
+		}
+		return newAccountFromPrivateKey(privateKey)
+	}
+
+	publicKey, err := k.ecdsaPublicKey()
+	if err != nil {
+		return nil, err
+	}
+
+	cli := k.cryptoClient()
+	acc := &Account{}
+	acc.PublicKey, err = cli.GetEcdsaPublicKeyJsonFormatStrFromPublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	acc.Address, err = cli.GetAddressFromPublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	return acc, nil
+}
+
+// cryptoClient returns crypto client of this key suite, not the one in config.
+func (k *HDKey) cryptoClient() hdCryptoClient {
+	if k.cryptography == cryptoConfig.Gm {
+		return crypto.GetGmCryptoClient()
+	}
+	return crypto.GetXchainCryptoClient()
+}
+
+func (k *HDKey) ecdsaPrivateKey() (*ecdsa.PrivateKey, error) {
+	if k.cryptography == cryptoConfig.Gm {
+		extendedKey := &gmKeychain.ExtendedKey{}
+		if err := json.Unmarshal([]byte(k.key), extendedKey); err != nil {
+			return nil, err
+		}
+		return extendedKey.ECPrivateKey()
+	}
+
+	extendedKey := &keychain.ExtendedKey{}
+	if err := json.Unmarshal([]byte(k.key), extendedKey); err != nil {
+		return nil, err
+	}
+	return extendedKey.ECPrivateKey()
+}
+
+func (k *HDKey) ecdsaPublicKey() (*ecdsa.PublicKey, error) {
+	if k.cryptography == cryptoConfig.Gm {
+		extendedKey := &gmKeychain.ExtendedKey{}
+		if err := json.Unmarshal([]byte(k.key), extendedKey); err != nil {
+			return nil, err
+		}
+		return extendedKey.ECPublicKey()
+	}
+
+	extendedKey := &keychain.ExtendedKey{}
+	if err := json.Unmarshal([]byte(k.key), extendedKey); err != nil {
+		return nil, err
+	}
+	return extendedKey.ECPublicKey()
+}
@@ -0,0 +1,113 @@
+package account
+
+import (
+	"testing"
+
+	"github.com/superconsensus/matrix-sdk-go/v2/common/config"
+)
+
+func TestParseDerivationPath(t *testing.T) {
+	testCase := []struct {
+		path   string
+		expect []uint32
+		hasErr bool
+	}{
+		{path: "m", expect: []uint32{}},
+		{path: "m/44'/0'/0'/0/1", expect: []uint32{44 + HardenedKeyStart, HardenedKeyStart, HardenedKeyStart, 0, 1}},
+		{path: "0/1h", expect: []uint32{0, 1 + HardenedKeyStart}},
+		{path: "m/a", hasErr: true},
+		{path: "m/2147483648", hasErr: true},
+		{path: "m//1", hasErr: true},
+	}
+
+	for _, c := range testCase {
+		indexes, err := ParseDerivationPath(c.path)
+		if c.hasErr {
+			if err == nil {
+				t.Errorf("ParseDerivationPath %s expect error", c.path)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseDerivationPath %s err: %v", c.path, err)
+			continue
+		}
+		if len(indexes) != len(c.expect) {
+			t.Errorf("ParseDerivationPath %s assert failed: %v", c.path, indexes)
+			continue
+		}
+		for i := range indexes {
+			if indexes[i] != c.expect[i] {
+				t.Errorf("ParseDerivationPath %s assert failed: %v", c.path, indexes)
+			}
+		}
+	}
+
+	if BIP44Path(0, 1, 0, 2) != "m/44'/0'/1'/0/2" {
+		t.Error("BIP44Path assert failed")
+	}
+}
+
+func TestDeriveAccount(t *testing.T) {
+	cfg := config.GetInstance()
+	defer cfg.SetXchainCrypto()
+
+	suites := []func(){cfg.SetXchainCrypto, cfg.SetGMCrypto}
+	for _, setSuite := range suites {
+		setSuite()
+
+		acc, err := CreateAccount(1, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		acc0, err := DeriveAccount(acc.Mnemonic, "m/44'/0'/0'/0/0", 1)
+		if err != nil {
+			t.Fatalf("DeriveAccount %s err: %v", cfg.Crypto, err)
+		}
+		acc0Again, _ := DeriveAccount(acc.Mnemonic, "m/44'/0'/0'/0/0", 1)
+		acc1, _ := DeriveAccount(acc.Mnemonic, "m/44'/0'/0'/0/1", 1)
+		if acc0.Address != acc0Again.Address || acc0.PrivateKey != acc0Again.PrivateKey {
+			t.Error("DeriveAccount same path assert failed")
+		}
+		if acc0.Address == acc1.Address || acc0.Address == acc.Address {
+			t.Error("DeriveAccount different path assert failed")
+		}
+
+		// public key derives the same normal child addresses, without private key.
+		masterKey, err := NewMasterKey(acc.Mnemonic, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		accountKey, err := masterKey.Derive("m/44'/0'/0'")
+		if err != nil {
+			t.Fatal(err)
+		}
+		pubKey, err := accountKey.Neuter()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pubKey.IsPrivate() || !accountKey.IsPrivate() {
+			t.Error("HDKey Neuter assert failed")
+		}
+		if _, err := pubKey.Child(HardenedKeyStart); err == nil {
+			t.Error("HDKey public key hardened child assert failed")
+		}
+
+		loaded, err := NewHDKeyFromString(pubKey.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		pubChild, err := loaded.Derive("0/1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		watchAcc, err := pubChild.Account()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if watchAcc.Address != acc1.Address || watchAcc.PrivateKey != "" {
+			t.Errorf("HDKey public derive %s assert failed", cfg.Crypto)
+		}
+	}
+}
@@ -0,0 +1,449 @@
+package account
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"hash"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	cryptoAccount "github.com/xuperchain/crypto/common/account"
+	cryptoConfig "github.com/xuperchain/crypto/core/config"
+	"github.com/xuperchain/crypto/gm/gmsm/sm2"
+	"golang.org/x/crypto/pbkdf2"
+
+	"github.com/superconsensus/matrix-sdk-go/v2/common"
+	"github.com/superconsensus/matrix-sdk-go/v2/common/config"
+	"github.com/superconsensus/matrix-sdk-go/v2/crypto"
+)
+
+const (
+	pemTypePrivateKey          = "PRIVATE KEY"
+	pemTypeEncryptedPrivateKey = "ENCRYPTED PRIVATE KEY"
+	pemTypeECPrivateKey        = "EC PRIVATE KEY"
+
+	pbkdf2Iterations = 100000
+	pbkdf2SaltSize   = 16
+	// pbkdf2MaxIterations bound of iteration count read from file, so a crafted key can not hang the decryption.
+	pbkdf2MaxIterations = 1 << 21
+)
+
+var (
+	oidPBES2          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2         = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+	oidHMACWithSHA1   = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidHMACWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidAES128CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES192CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}
+	oidAES256CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+)
+
+// encryptedPrivateKeyInfo PKCS#8 EncryptedPrivateKeyInfo, RFC 5208.
+type encryptedPrivateKeyInfo struct {
+	Algo          pkix.AlgorithmIdentifier
+	EncryptedData []byte
+}
+
+// pbes2Params PBES2-params, RFC 8018.
+type pbes2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+// pbkdf2Params PBKDF2-params, RFC 8018, PRF is hmacWithSHA1 if absent.
+type pbkdf2Params struct {
+	Salt           []byte
+	IterationCount int
+	KeyLength      int                      `asn1:"optional"`
+	PRF            pkix.AlgorithmIdentifier `asn1:"optional"`
+}
+
+// ExportPrivateKeyHex export private key as hex string of 32 bytes, without curve information.
+func ExportPrivateKeyHex(acc *Account) (string, error) {
+	privateKey, err := acc.ecdsaPrivateKey()
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(privateKeyBytes(privateKey)), nil
+}
+
+// ImportPrivateKeyHex import account from hex private key, the curve is the one of crypto suite in config,
+// P-256 for xchain and SM2 for gm.
+func ImportPrivateKeyHex(hexKey string) (*Account, error) {
+	keyBytes, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(hexKey), "0x"))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid hex private key")
+	}
+
+	curve := elliptic.P256()
+	if config.GetInstance().Crypto == config.CRYPTO_GM {
+		curve = sm2.P256Sm2()
+	}
+
+	d := new(big.Int).SetBytes(keyBytes)
+	if len(keyBytes) != 32 || d.Sign() == 0 || d.Cmp(curve.Params().N) >= 0 {
+		return nil, errors.New("invalid hex private key")
+	}
+
+	privateKey := &ecdsa.PrivateKey{D: d}
+	privateKey.Curve = curve
+	privateKey.X, privateKey.Y = curve.ScalarBaseMult(keyBytes)
+	return newAccountFromPrivateKey(privateKey)
+}
+
+// ExportPrivateKeyPEM export private key as PKCS#8 PEM.
+// If passwd is not empty, the key is encrypted with PBES2 (PBKDF2-HMAC-SHA256 and AES-256-CBC),
+// the same as `openssl pkcs8 -topk8 -v2 aes-256-cbc`.
+//
+// Parameters:
+//   - `acc`：   账户，必须包含私钥。
+//   - `passwd`：密码，为空时不加密。
+func ExportPrivateKeyPEM(acc *Account, passwd string) ([]byte, error) {
+	privateKey, err := acc.ecdsaPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	der, err := marshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	if passwd == "" {
+		return pem.EncodeToMemory(&pem.Block{Type: pemTypePrivateKey, Bytes: der}), nil
+	}
+
+	der, err = encryptPKCS8(der, passwd)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: pemTypeEncryptedPrivateKey, Bytes: der}), nil
+}
+
+// ImportPrivateKeyPEM import account from PEM private key, both PKCS#8 (encrypted or not) and SEC1 EC private key are supported.
+//
+// Parameters:
+//   - `data`：  PEM 内容。
+//   - `passwd`：密码，私钥未加密时忽略。
+func ImportPrivateKeyPEM(data []byte, passwd string) (*Account, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var (
+		privateKey *ecdsa.PrivateKey
+		err        error
+	)
+	switch block.Type {
+	case pemTypePrivateKey:
+		privateKey, err = parsePKCS8PrivateKey(block.Bytes)
+	case pemTypeEncryptedPrivateKey:
+		var der []byte
+		der, err = decryptPKCS8(block.Bytes, passwd)
+		if err != nil {
+			return nil, err
+		}
+		if privateKey, err = parsePKCS8PrivateKey(der); err != nil {
+			return nil, common.ErrInvalidPassword
+		}
+	case pemTypeECPrivateKey:
+		privateKey, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM type %s", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newAccountFromPrivateKey(privateKey)
+}
+
+// SaveAccountToPlainFile save account to plain files, the same layout as xchain CLI keys directory,
+// which can be loaded by GetAccountFromPlainFile.
+//
+// 指定路径下的结构如下:
+//   - keys
+//     |-- address
+//     |-- private.key
+//     |-- public.key
+func SaveAccountToPlainFile(acc *Account, path string) error {
+	if acc.IsWatchOnly() {
+		return common.ErrWatchOnlyAccount
+	}
+	if err := common.PathExistsAndMkdir(path); err != nil {
+		return err
+	}
+
+	files := []struct {
+		name    string
+		content string
+		perm    os.FileMode
+	}{
+		{name: "address", content: acc.Address, perm: 0644},
+		{name: "public.key", content: acc.PublicKey, perm: 0644},
+		{name: "private.key", content: acc.PrivateKey, perm: 0600},
+	}
+	for _, f := range files {
+		if err := ioutil.WriteFile(filepath.Join(path, f.name), []byte(f.content), f.perm); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SaveAccountToFile save account private key encrypted by password, the same format as xchain CLI
+// encrypted keys, which can be loaded by GetAccountFromFile.
+//
+// Parameters:
+//   - `acc`：   账户，必须包含私钥。
+//   - `path`：  保存路径。
+//   - `passwd`：密码。
+func SaveAccountToFile(acc *Account, path, passwd string) error {
+	if acc.IsWatchOnly() {
+		return common.ErrWatchOnlyAccount
+	}
+	if err := common.PathExistsAndMkdir(path); err != nil {
+		return err
+	}
+
+	cli, err := cryptoClientByKey(acc.PrivateKey)
+	if err != nil {
+		return err
+	}
+	encrypted, err := cli.EncryptAccount(&cryptoAccount.ECDSAAccount{
+		Address:        acc.Address,
+		JsonPrivateKey: acc.PrivateKey,
+		JsonPublicKey:  acc.PublicKey,
+	}, passwd)
+	if err != nil {
+		return err
+	}
+	return cli.SaveEncryptedAccountToFile(encrypted, path)
+}
+
+// newAccountFromPrivateKey returns account of the private key, crypto client is chosen by the key curve.
+func newAccountFromPrivateKey(privateKey *ecdsa.PrivateKey) (*Account, error) {
+	cli, err := cryptoClientByCurve(privateKey.Params().Name)
+	if err != nil {
+		return nil, err
+	}
+
+	acc := &Account{}
+	acc.PrivateKey, err = cli.GetEcdsaPrivateKeyJsonFormatStr(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	acc.PublicKey, err = cli.GetEcdsaPublicKeyJsonFormatStr(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	acc.Address, err = cli.GetAddressFromPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	return acc, nil
+}
+
+// ecdsaPrivateKey returns parsed private key of the account.
+func (a *Account) ecdsaPrivateKey() (*ecdsa.PrivateKey, error) {
+	if a.IsWatchOnly() {
+		return nil, common.ErrWatchOnlyAccount
+	}
+	cli, err := cryptoClientByKey(a.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	return cli.GetEcdsaPrivateKeyFromJsonStr(a.PrivateKey)
+}
+
+// cryptoClientByKey returns crypto client by curve name of JSON encoded key.
+func cryptoClientByKey(jsonKey string) (hdCryptoClient, error) {
+	key := &struct{ Curvname string }{}
+	if err := json.Unmarshal([]byte(jsonKey), key); err != nil {
+		return nil, errors.Wrap(err, "invalid JSON key")
+	}
+	return cryptoClientByCurve(key.Curvname)
+}
+
+func cryptoClientByCurve(curveName string) (hdCryptoClient, error) {
+	switch curveName {
+	case cryptoConfig.CurveNist:
+		return crypto.GetXchainCryptoClient(), nil
+	case cryptoConfig.CurveGm:
+		return crypto.GetGmCryptoClient(), nil
+	default:
+		return nil, fmt.Errorf("unsupported curve %s", curveName)
+	}
+}
+
+func privateKeyBytes(privateKey *ecdsa.PrivateKey) []byte {
+	size := (privateKey.Params().BitSize + 7) / 8
+	keyBytes := privateKey.D.Bytes()
+	if len(keyBytes) >= size {
+		return keyBytes
+	}
+	return append(make([]byte, size-len(keyBytes)), keyBytes...)
+}
+
+func marshalPKCS8PrivateKey(privateKey *ecdsa.PrivateKey) ([]byte, error) {
+	if privateKey.Params().Name == cryptoConfig.CurveGm {
+		return sm2.MarshalSm2UnecryptedPrivateKey(&sm2.PrivateKey{
+			PublicKey: sm2.PublicKey{Curve: privateKey.Curve, X: privateKey.X, Y: privateKey.Y},
+			D:         privateKey.D,
+		})
+	}
+	return x509.MarshalPKCS8PrivateKey(privateKey)
+}
+
+func parsePKCS8PrivateKey(der []byte) (*ecdsa.PrivateKey, error) {
+	if key, err := x509.ParsePKCS8PrivateKey(der); err == nil {
+		privateKey, ok := key.(*ecdsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("unsupported private key type %T", key)
+		}
+		return privateKey, nil
+	}
+
+	// crypto/x509 does not know SM2 curve.
+	key, err := sm2.ParsePKCS8UnecryptedPrivateKey(der)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid PKCS#8 private key")
+	}
+	privateKey := &ecdsa.PrivateKey{D: key.D}
+	privateKey.Curve, privateKey.X, privateKey.Y = key.Curve, key.X, key.Y
+	return privateKey, nil
+}
+
+func encryptPKCS8(der []byte, passwd string) ([]byte, error) {
+	salt := make([]byte, pbkdf2SaltSize)
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	if _, err := rand.Read(iv); err != nil {
+		return nil, err
+	}
+
+	key := pbkdf2.Key([]byte(passwd), salt, pbkdf2Iterations, 32, sha256.New)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	padding := aes.BlockSize - len(der)%aes.BlockSize
+	plain := append(append([]byte{}, der...), bytes.Repeat([]byte{byte(padding)}, padding)...)
+	encrypted := make([]byte, len(plain))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(encrypted, plain)
+
+	kdfParams, err := asn1.Marshal(pbkdf2Params{
+		Salt:           salt,
+		IterationCount: pbkdf2Iterations,
+		PRF:            pkix.AlgorithmIdentifier{Algorithm: oidHMACWithSHA256, Parameters: asn1.NullRawValue},
+	})
+	if err != nil {
+		return nil, err
+	}
+	ivParams, err := asn1.Marshal(iv)
+	if err != nil {
+		return nil, err
+	}
+	params, err := asn1.Marshal(pbes2Params{
+		KeyDerivationFunc: pkix.AlgorithmIdentifier{Algorithm: oidPBKDF2, Parameters: asn1.RawValue{FullBytes: kdfParams}},
+		EncryptionScheme:  pkix.AlgorithmIdentifier{Algorithm: oidAES256CBC, Parameters: asn1.RawValue{FullBytes: ivParams}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return asn1.Marshal(encryptedPrivateKeyInfo{
+		Algo:          pkix.AlgorithmIdentifier{Algorithm: oidPBES2, Parameters: asn1.RawValue{FullBytes: params}},
+		EncryptedData: encrypted,
+	})
+}
+
+func decryptPKCS8(der []byte, passwd string) ([]byte, error) {
+	info := &encryptedPrivateKeyInfo{}
+	if _, err := asn1.Unmarshal(der, info); err != nil {
+		return nil, errors.Wrap(err, "invalid encrypted PKCS#8 private key")
+	}
+	if !info.Algo.Algorithm.Equal(oidPBES2) {
+		return nil, fmt.Errorf("unsupported encryption algorithm %s", info.Algo.Algorithm)
+	}
+
+	params := &pbes2Params{}
+	if _, err := asn1.Unmarshal(info.Algo.Parameters.FullBytes, params); err != nil {
+		return nil, errors.Wrap(err, "invalid PBES2 params")
+	}
+	if !params.KeyDerivationFunc.Algorithm.Equal(oidPBKDF2) {
+		return nil, fmt.Errorf("unsupported key derivation function %s", params.KeyDerivationFunc.Algorithm)
+	}
+	kdfParams := &pbkdf2Params{}
+	if _, err := asn1.Unmarshal(params.KeyDerivationFunc.Parameters.FullBytes, kdfParams); err != nil {
+		return nil, errors.Wrap(err, "invalid PBKDF2 params")
+	}
+
+	var prf func() hash.Hash
+	switch {
+	case len(kdfParams.PRF.Algorithm) == 0, kdfParams.PRF.Algorithm.Equal(oidHMACWithSHA1):
+		prf = sha1.New
+	case kdfParams.PRF.Algorithm.Equal(oidHMACWithSHA256):
+		prf = sha256.New
+	default:
+		return nil, fmt.Errorf("unsupported PBKDF2 PRF %s", kdfParams.PRF.Algorithm)
+	}
+
+	var keyLen int
+	switch {
+	case params.EncryptionScheme.Algorithm.Equal(oidAES128CBC):
+		keyLen = 16
+	case params.EncryptionScheme.Algorithm.Equal(oidAES192CBC):
+		keyLen = 24
+	case params.EncryptionScheme.Algorithm.Equal(oidAES256CBC):
+		keyLen = 32
+	default:
+		return nil, fmt.Errorf("unsupported encryption scheme %s", params.EncryptionScheme.Algorithm)
+	}
+	if kdfParams.IterationCount < 1 || kdfParams.IterationCount > pbkdf2MaxIterations {
+		return nil, fmt.Errorf("PBKDF2 iteration count %d out of range", kdfParams.IterationCount)
+	}
+	if kdfParams.KeyLength != 0 && kdfParams.KeyLength != keyLen {
+		return nil, fmt.Errorf("PBKDF2 key length %d does not match encryption scheme", kdfParams.KeyLength)
+	}
+	var iv []byte
+	if _, err := asn1.Unmarshal(params.EncryptionScheme.Parameters.FullBytes, &iv); err != nil || len(iv) != aes.BlockSize {
+		return nil, errors.New("invalid AES-CBC iv")
+	}
+	if len(info.EncryptedData) == 0 || len(info.EncryptedData)%aes.BlockSize != 0 {
+		return nil, common.ErrInvalidPassword
+	}
+
+	key := pbkdf2.Key([]byte(passwd), kdfParams.Salt, kdfParams.IterationCount, keyLen, prf)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	plain := make([]byte, len(info.EncryptedData))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plain, info.EncryptedData)
+
+	// wrong password results in invalid padding in most cases, otherwise the DER parse fails later.
+	padding := int(plain[len(plain)-1])
+	if padding == 0 || padding > aes.BlockSize || !bytes.Equal(plain[len(plain)-padding:], bytes.Repeat([]byte{byte(padding)}, padding)) {
+		return nil, common.ErrInvalidPassword
+	}
+	return plain[:len(plain)-padding], nil
+}
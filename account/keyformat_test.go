@@ -0,0 +1,153 @@
+package account
+
+import (
+	"crypto/aes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/superconsensus/matrix-sdk-go/v2/common"
+	"github.com/superconsensus/matrix-sdk-go/v2/common/config"
+)
+
+func TestKeyFormat(t *testing.T) {
+	cfg := config.GetInstance()
+	defer cfg.SetXchainCrypto()
+
+	suites := []func(){cfg.SetXchainCrypto, cfg.SetGMCrypto}
+	for _, setSuite := range suites {
+		setSuite()
+
+		acc, err := CreateAccount(1, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		hexKey, err := ExportPrivateKeyHex(acc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(hexKey) != 64 {
+			t.Errorf("ExportPrivateKeyHex %s length assert failed: %s", cfg.Crypto, hexKey)
+		}
+		fromHex, err := ImportPrivateKeyHex(hexKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fromHex.Address != acc.Address || fromHex.PrivateKey != acc.PrivateKey {
+			t.Errorf("ImportPrivateKeyHex %s assert failed", cfg.Crypto)
+		}
+
+		for _, passwd := range []string{"", "123"} {
+			data, err := ExportPrivateKeyPEM(acc, passwd)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if encrypted := strings.Contains(string(data), "ENCRYPTED"); encrypted != (passwd != "") {
+				t.Errorf("ExportPrivateKeyPEM %s encrypted assert failed", cfg.Crypto)
+			}
+			fromPEM, err := ImportPrivateKeyPEM(data, passwd)
+			if err != nil {
+				t.Fatalf("ImportPrivateKeyPEM %s err: %v", cfg.Crypto, err)
+			}
+			if fromPEM.Address != acc.Address || fromPEM.PublicKey != acc.PublicKey {
+				t.Errorf("ImportPrivateKeyPEM %s assert failed", cfg.Crypto)
+			}
+			if passwd != "" {
+				if _, err := ImportPrivateKeyPEM(data, "456"); !errors.Is(err, common.ErrInvalidPassword) {
+					t.Errorf("ImportPrivateKeyPEM %s wrong password assert failed: %v", cfg.Crypto, err)
+				}
+			}
+		}
+
+		dir := "./keyformat/" + cfg.Crypto
+		if err := SaveAccountToPlainFile(acc, dir+"/plain"); err != nil {
+			t.Fatal(err)
+		}
+		fromPlain, err := GetAccountFromPlainFile(dir + "/plain")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fromPlain.Address != acc.Address || fromPlain.PrivateKey != acc.PrivateKey || fromPlain.PublicKey != acc.PublicKey {
+			t.Errorf("SaveAccountToPlainFile %s assert failed", cfg.Crypto)
+		}
+
+		if err := SaveAccountToFile(acc, dir+"/encrypted", "123"); err != nil {
+			t.Fatal(err)
+		}
+		fromFile, err := GetAccountFromFile(dir+"/encrypted/", "123")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fromFile.Address != acc.Address || fromFile.PrivateKey != acc.PrivateKey {
+			t.Errorf("SaveAccountToFile %s assert failed", cfg.Crypto)
+		}
+	}
+	os.RemoveAll("./keyformat")
+
+	watchOnly := &Account{Address: "a", PublicKey: "b"}
+	if _, err := ExportPrivateKeyHex(watchOnly); !errors.Is(err, common.ErrWatchOnlyAccount) {
+		t.Error("ExportPrivateKeyHex watch only account assert failed", err)
+	}
+	if err := SaveAccountToPlainFile(watchOnly, "./keyformat"); !errors.Is(err, common.ErrWatchOnlyAccount) {
+		t.Error("SaveAccountToPlainFile watch only account assert failed", err)
+	}
+
+	if _, err := ImportPrivateKeyHex("1234"); err == nil {
+		t.Error("ImportPrivateKeyHex short key assert failed")
+	}
+}
+
+func TestImportSEC1PrivateKeyPEM(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalECPrivateKey(privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	acc, err := ImportPrivateKeyPEM(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect, _ := newAccountFromPrivateKey(privateKey)
+	if acc.Address != expect.Address {
+		t.Error("ImportPrivateKeyPEM SEC1 assert failed")
+	}
+}
+
+func TestDecryptPKCS8Params(t *testing.T) {
+	iv, _ := asn1.Marshal(make([]byte, aes.BlockSize))
+	cases := []struct {
+		name   string
+		params pbkdf2Params
+	}{
+		{name: "huge iteration count", params: pbkdf2Params{Salt: []byte("salt"), IterationCount: 1 << 30}},
+		{name: "zero iteration count", params: pbkdf2Params{Salt: []byte("salt")}},
+		{name: "wrong key length", params: pbkdf2Params{Salt: []byte("salt"), IterationCount: 1, KeyLength: 16}},
+	}
+	for _, c := range cases {
+		kdfParams, _ := asn1.Marshal(c.params)
+		params, _ := asn1.Marshal(pbes2Params{
+			KeyDerivationFunc: pkix.AlgorithmIdentifier{Algorithm: oidPBKDF2, Parameters: asn1.RawValue{FullBytes: kdfParams}},
+			EncryptionScheme:  pkix.AlgorithmIdentifier{Algorithm: oidAES256CBC, Parameters: asn1.RawValue{FullBytes: iv}},
+		})
+		der, _ := asn1.Marshal(encryptedPrivateKeyInfo{
+			Algo:          pkix.AlgorithmIdentifier{Algorithm: oidPBES2, Parameters: asn1.RawValue{FullBytes: params}},
+			EncryptedData: make([]byte, aes.BlockSize),
+		})
+		if _, err := decryptPKCS8(der, "123"); err == nil || errors.Is(err, common.ErrInvalidPassword) {
+			t.Errorf("decryptPKCS8 %s assert failed: %v", c.name, err)
+		}
+	}
+}
@@ -0,0 +1,481 @@
+package account
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/superconsensus/matrix-sdk-go/v2/common"
+)
+
+const (
+	keystoreFileExt = ".json"
+	keystoreVersion = 1
+
+	// scrypt parameters, same cost as the "light" parameters used by most wallets.
+	scryptN      = 1 << 15
+	scryptR      = 8
+	scryptP      = 1
+	scryptKeyLen = 32
+	scryptSalt   = 32
+)
+
+// Keystore manages many accounts in one directory.
+//
+// Every account is saved to its own file named by address, the private key and mnemonic
+// are encrypted by the account password (scrypt + AES-GCM), address, public key, label and
+// contract account binding are saved in plain text so they can be listed without password.
+type Keystore struct {
+	dir string
+	mu  sync.RWMutex
+}
+
+// KeyInfo keystore account info which can be read without password.
+type KeyInfo struct {
+	Address         string `json:"address"`
+	PublicKey       string `json:"publicKey"`
+	Label           string `json:"label,omitempty"`
+	ContractAccount string `json:"contractAccount,omitempty"`
+	CreatedAt       int64  `json:"createdAt"`
+}
+
+type keyFile struct {
+	Version int `json:"version"`
+	KeyInfo
+	Crypto *encryptedKey `json:"crypto"`
+}
+
+// encryptedKey password encrypted data, KDF is scrypt and cipher is AES-256-GCM.
+type encryptedKey struct {
+	Cipher     string `json:"cipher"`
+	CipherText string `json:"cipherText"`
+	Nonce      string `json:"nonce"`
+	KDF        string `json:"kdf"`
+	Salt       string `json:"salt"`
+	N          int    `json:"n"`
+	R          int    `json:"r"`
+	P          int    `json:"p"`
+}
+
+type secretKey struct {
+	PrivateKey string `json:"privateKey"`
+	Mnemonic   string `json:"mnemonic,omitempty"`
+}
+
+// NewKeystore open keystore in dir, dir and all parents will be created if not exist.
+func NewKeystore(dir string) (*Keystore, error) {
+	if dir == "" {
+		return nil, common.ErrInvalidParam
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &Keystore{dir: dir}, nil
+}
+
+// CreateAccount create a new account and save it to keystore.
+//
+// Parameters:
+//   - `passwd`：密码。
+//   - `label`：账户标签，可以为空，不为空时在 keystore 中唯一。
+//   - `strength`：助记词强度。
+//   - `language`：助记词语言。
+func (ks *Keystore) CreateAccount(passwd, label string, strength uint8, language int) (*Account, error) {
+	acc, err := CreateAccount(strength, language)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ks.Import(acc, passwd, label); err != nil {
+		return nil, err
+	}
+	return acc, nil
+}
+
+// Import save an existing account to keystore.
+// The address and public key saved are derived from the private key, acc.Address must match it.
+// If the account has contract account, the binding will be saved too.
+func (ks *Keystore) Import(acc *Account, passwd, label string) error {
+	if acc == nil || acc.Address == "" || acc.PrivateKey == "" {
+		return common.ErrInvalidAccount
+	}
+	if !isAKAddress(acc.Address) {
+		return errors.Wrapf(common.ErrInvalidAddress, "invalid AK address %s", acc.Address)
+	}
+	privateKey, err := acc.ecdsaPrivateKey()
+	if err != nil {
+		return errors.Wrap(common.ErrInvalidAccount, err.Error())
+	}
+	derived, err := newAccountFromPrivateKey(privateKey)
+	if err != nil {
+		return err
+	}
+	if derived.Address != acc.Address {
+		return errors.Wrapf(common.ErrInvalidAccount, "address %s does not match private key", acc.Address)
+	}
+	if err := validateLabel(label); err != nil {
+		return err
+	}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	if _, err := os.Stat(ks.keyPath(derived.Address)); err == nil {
+		return common.ErrAccountExists
+	}
+	if label != "" {
+		if _, err := ks.findByLabel(label); err == nil {
+			return common.ErrLabelExists
+		}
+	}
+
+	secret, err := json.Marshal(&secretKey{PrivateKey: acc.PrivateKey, Mnemonic: acc.Mnemonic})
+	if err != nil {
+		return err
+	}
+	crypto, err := encryptWithPassword(secret, passwd)
+	if err != nil {
+		return err
+	}
+
+	kf := &keyFile{
+		Version: keystoreVersion,
+		KeyInfo: KeyInfo{
+			Address:         derived.Address,
+			PublicKey:       derived.PublicKey,
+			Label:           label,
+			ContractAccount: acc.GetContractAccount(),
+			CreatedAt:       time.Now().Unix(),
+		},
+		Crypto: crypto,
+	}
+	return ks.writeKeyFile(kf)
+}
+
+// Unlock decrypt account by address or label.
+// If a contract account was bound to this account, the returned account has the contract account set.
+func (ks *Keystore) Unlock(addressOrLabel, passwd string) (*Account, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	kf, err := ks.find(addressOrLabel)
+	if err != nil {
+		return nil, err
+	}
+
+	plain, err := decryptWithPassword(kf.Crypto, passwd)
+	if err != nil {
+		return nil, err
+	}
+	secret := &secretKey{}
+	if err := json.Unmarshal(plain, secret); err != nil {
+		return nil, common.ErrInvalidPassword
+	}
+
+	acc := &Account{
+		Address:    kf.Address,
+		PublicKey:  kf.PublicKey,
+		PrivateKey: secret.PrivateKey,
+		Mnemonic:   secret.Mnemonic,
+	}
+	if kf.ContractAccount != "" {
+		if err := acc.SetContractAccount(kf.ContractAccount); err != nil {
+			return nil, err
+		}
+	}
+	return acc, nil
+}
+
+// List returns all accounts info in keystore, sorted by address, corrupted key files are skipped.
+func (ks *Keystore) List() ([]*KeyInfo, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	kfs, err := ks.readAll()
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]*KeyInfo, 0, len(kfs))
+	for _, kf := range kfs {
+		info := kf.KeyInfo
+		infos = append(infos, &info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Address < infos[j].Address
+	})
+	return infos, nil
+}
+
+// Get returns account info by address or label.
+func (ks *Keystore) Get(addressOrLabel string) (*KeyInfo, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	kf, err := ks.find(addressOrLabel)
+	if err != nil {
+		return nil, err
+	}
+	info := kf.KeyInfo
+	return &info, nil
+}
+
+// Delete remove account from keystore, password is required to avoid deleting by mistake.
+func (ks *Keystore) Delete(addressOrLabel, passwd string) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	kf, err := ks.find(addressOrLabel)
+	if err != nil {
+		return err
+	}
+	if _, err := decryptWithPassword(kf.Crypto, passwd); err != nil {
+		return err
+	}
+	return os.Remove(ks.keyPath(kf.Address))
+}
+
+// SetLabel set or change account label, empty label removes it, an AK address can not be a label.
+func (ks *Keystore) SetLabel(addressOrLabel, label string) error {
+	if err := validateLabel(label); err != nil {
+		return err
+	}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	kf, err := ks.find(addressOrLabel)
+	if err != nil {
+		return err
+	}
+	if label != "" {
+		if other, err := ks.findByLabel(label); err == nil && other.Address != kf.Address {
+			return common.ErrLabelExists
+		}
+	}
+	kf.Label = label
+	return ks.writeKeyFile(kf)
+}
+
+// BindContractAccount bind contract account to account, such as XC1111111111111111@xuper.
+// Unlock will set the contract account for the account.
+func (ks *Keystore) BindContractAccount(addressOrLabel, contractAccount string) error {
+	if err := (&Account{}).SetContractAccount(contractAccount); err != nil {
+		return err
+	}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	kf, err := ks.find(addressOrLabel)
+	if err != nil {
+		return err
+	}
+	kf.ContractAccount = contractAccount
+	return ks.writeKeyFile(kf)
+}
+
+// UnbindContractAccount remove contract account binding from account.
+func (ks *Keystore) UnbindContractAccount(addressOrLabel string) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	kf, err := ks.find(addressOrLabel)
+	if err != nil {
+		return err
+	}
+	kf.ContractAccount = ""
+	return ks.writeKeyFile(kf)
+}
+
+func (ks *Keystore) keyPath(address string) string {
+	return filepath.Join(ks.dir, address+keystoreFileExt)
+}
+
+func (ks *Keystore) find(addressOrLabel string) (*keyFile, error) {
+	if addressOrLabel == "" {
+		return nil, common.ErrInvalidParam
+	}
+	// only an AK address is read as a path, so labels like "../a" won't be.
+	if isAKAddress(addressOrLabel) {
+		kf, err := ks.readKeyFile(ks.keyPath(addressOrLabel))
+		if err == nil {
+			return kf, nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	return ks.findByLabel(addressOrLabel)
+}
+
+func (ks *Keystore) findByLabel(label string) (*keyFile, error) {
+	kfs, err := ks.readAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, kf := range kfs {
+		if kf.Label == label {
+			return kf, nil
+		}
+	}
+	return nil, common.ErrAccountNotFound
+}
+
+func (ks *Keystore) readAll() ([]*keyFile, error) {
+	entries, err := ioutil.ReadDir(ks.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	kfs := make([]*keyFile, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), keystoreFileExt) {
+			continue
+		}
+		kf, err := ks.readKeyFile(filepath.Join(ks.dir, e.Name()))
+		if err != nil {
+			// a corrupted or unreadable file does not hide other accounts.
+			continue
+		}
+		kfs = append(kfs, kf)
+	}
+	return kfs, nil
+}
+
+// isAKAddress returns true if s is an AK address with valid checksum, only those are key file names.
+func isAKAddress(s string) bool {
+	addr, err := ParseAddress(s)
+	return err == nil && addr.IsAK() && addr.ChecksumValid
+}
+
+// validateLabel a label can not be an AK address, or Get by it would be ambiguous.
+func validateLabel(label string) error {
+	if isAKAddress(label) {
+		return errors.Wrapf(common.ErrInvalidParam, "label %s is an address", label)
+	}
+	return nil
+}
+
+func (ks *Keystore) readKeyFile(path string) (*keyFile, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	kf := &keyFile{}
+	if err := json.Unmarshal(data, kf); err != nil {
+		return nil, errors.Wrapf(err, "invalid keystore file %s", path)
+	}
+	return kf, nil
+}
+
+// writeKeyFile write to a temp file first and rename, so a crash never leaves a broken key file.
+func (ks *Keystore) writeKeyFile(kf *keyFile) error {
+	data, err := json.MarshalIndent(kf, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(ks.dir, "."+kf.Address+"-*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), ks.keyPath(kf.Address))
+}
+
+func encryptWithPassword(plain []byte, passwd string) (*encryptedKey, error) {
+	salt := make([]byte, scryptSalt)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	key, err := scrypt.Key([]byte(passwd), salt, scryptN, scryptR, scryptP, scryptKeyLen)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return &encryptedKey{
+		Cipher:     "aes-256-gcm",
+		CipherText: hex.EncodeToString(gcm.Seal(nil, nonce, plain, nil)),
+		Nonce:      hex.EncodeToString(nonce),
+		KDF:        "scrypt",
+		Salt:       hex.EncodeToString(salt),
+		N:          scryptN,
+		R:          scryptR,
+		P:          scryptP,
+	}, nil
+}
+
+func decryptWithPassword(ek *encryptedKey, passwd string) ([]byte, error) {
+	if ek == nil || ek.KDF != "scrypt" || ek.Cipher != "aes-256-gcm" {
+		return nil, errors.New("unsupported keystore crypto")
+	}
+
+	salt, err := hex.DecodeString(ek.Salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := hex.DecodeString(ek.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	cipherText, err := hex.DecodeString(ek.CipherText)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := scrypt.Key([]byte(passwd), salt, ek.N, ek.R, ek.P, scryptKeyLen)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != gcm.NonceSize() {
+		return nil, common.ErrInvalidPassword
+	}
+
+	plain, err := gcm.Open(nil, nonce, cipherText, nil)
+	if err != nil {
+		return nil, common.ErrInvalidPassword
+	}
+	return plain, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,153 @@
+package account
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/superconsensus/matrix-sdk-go/v2/common"
+)
+
+func TestKeystore(t *testing.T) {
+	dir := "./ks/a/b"
+	defer os.RemoveAll("./ks")
+
+	ks, err := NewKeystore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	alice, err := ks.CreateAccount("123", "alice", 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, _ := CreateAccount(1, 2)
+	if err := ks.Import(bob, "456", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ks.Import(bob, "456", ""); !errors.Is(err, common.ErrAccountExists) {
+		t.Error("Keystore import exists account assert failed", err)
+	}
+	carol, _ := CreateAccount(1, 1)
+	if err := ks.Import(carol, "789", "alice"); !errors.Is(err, common.ErrLabelExists) {
+		t.Error("Keystore import exists label assert failed", err)
+	}
+
+	invalid := []struct {
+		name string
+		acc  *Account
+		err  error
+	}{
+		{name: "path address", acc: &Account{Address: "../x", PrivateKey: carol.PrivateKey}, err: common.ErrInvalidAddress},
+		{name: "other address", acc: &Account{Address: alice.Address, PrivateKey: carol.PrivateKey}, err: common.ErrInvalidAccount},
+	}
+	for _, c := range invalid {
+		if err := ks.Import(c.acc, "789", ""); !errors.Is(err, c.err) {
+			t.Errorf("Keystore import %s expect err %v, got %v", c.name, c.err, err)
+		}
+	}
+
+	// a corrupted key file does not break listing.
+	if err := ioutil.WriteFile(filepath.Join(dir, "broken.json"), []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	infos, err := ks.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(infos) != 2 {
+		t.Errorf("Keystore list expect 2 accounts, got %d", len(infos))
+	}
+
+	cases := []struct {
+		key    string
+		passwd string
+		expect *Account
+		err    error
+	}{
+		{key: "alice", passwd: "123", expect: alice},
+		{key: alice.Address, passwd: "123", expect: alice},
+		{key: bob.Address, passwd: "456", expect: bob},
+		{key: "alice", passwd: "456", err: common.ErrInvalidPassword},
+		{key: "nobody", passwd: "123", err: common.ErrAccountNotFound},
+		{key: "../../keystore", passwd: "123", err: common.ErrAccountNotFound},
+	}
+	for _, c := range cases {
+		acc, err := ks.Unlock(c.key, c.passwd)
+		if c.err != nil {
+			if !errors.Is(err, c.err) {
+				t.Errorf("Keystore unlock %s expect err %v, got %v", c.key, c.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Keystore unlock %s err: %v", c.key, err)
+			continue
+		}
+		if acc.Address != c.expect.Address || acc.PrivateKey != c.expect.PrivateKey ||
+			acc.PublicKey != c.expect.PublicKey || acc.Mnemonic != c.expect.Mnemonic {
+			t.Errorf("Keystore unlock %s account not match", c.key)
+		}
+	}
+
+	if err := ks.BindContractAccount("alice", "XC123@xuper"); !errors.Is(err, common.ErrInvalidContractAccount) {
+		t.Error("Keystore bind invalid contract account assert failed", err)
+	}
+	if err := ks.BindContractAccount("alice", "XC1234567812345678@xuper"); err != nil {
+		t.Fatal(err)
+	}
+	acc, err := ks.Unlock(alice.Address, "123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if acc.GetContractAccount() != "XC1234567812345678@xuper" {
+		t.Error("Keystore unlock contract account assert failed")
+	}
+	if err := ks.UnbindContractAccount("alice"); err != nil {
+		t.Fatal(err)
+	}
+	if info, _ := ks.Get("alice"); info == nil || info.ContractAccount != "" {
+		t.Error("Keystore unbind contract account assert failed")
+	}
+
+	if err := ks.SetLabel(bob.Address, "alice"); !errors.Is(err, common.ErrLabelExists) {
+		t.Error("Keystore set exists label assert failed", err)
+	}
+	if err := ks.SetLabel(bob.Address, alice.Address); !errors.Is(err, common.ErrInvalidParam) {
+		t.Error("Keystore set address label assert failed", err)
+	}
+	if err := ks.SetLabel(bob.Address, "bob"); err != nil {
+		t.Fatal(err)
+	}
+	if info, _ := ks.Get("bob"); info == nil || info.Address != bob.Address {
+		t.Error("Keystore get by label assert failed")
+	}
+
+	if err := ks.Delete("bob", "123"); !errors.Is(err, common.ErrInvalidPassword) {
+		t.Error("Keystore delete with wrong password assert failed", err)
+	}
+	if err := ks.Delete("bob", "456"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ks.Get(bob.Address); !errors.Is(err, common.ErrAccountNotFound) {
+		t.Error("Keystore delete assert failed", err)
+	}
+}
+
+func TestCreateAndSaveAccountToNestedPath(t *testing.T) {
+	defer os.RemoveAll("./nested")
+	acc, err := CreateAndSaveAccountToFile("./nested/a/b", "123", 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	acc1, err := GetAccountFromFile("./nested/a/b/", "123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if acc1.Address != acc.Address {
+		t.Error("CreateAndSaveAccountToFile nested path assert failed")
+	}
+}
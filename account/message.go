@@ -0,0 +1,62 @@
+package account
+
+import (
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/xuperchain/crypto/core/hash"
+
+	"github.com/superconsensus/matrix-sdk-go/v2/common"
+	"github.com/superconsensus/matrix-sdk-go/v2/crypto"
+)
+
+// MessagePrefix prefix of signed message, so that a message signature can never be a valid transaction signature.
+const MessagePrefix = "\x19XuperChain Signed Message:\n"
+
+// MessageHash returns the digest to be signed for msg: DoubleSha256(MessagePrefix + len(msg) + msg).
+func MessageHash(msg []byte) []byte {
+	data := make([]byte, 0, len(MessagePrefix)+20+len(msg))
+	data = append(data, MessagePrefix...)
+	data = strconv.AppendInt(data, int64(len(msg)), 10)
+	data = append(data, msg...)
+	return hash.DoubleSha256(data)
+}
+
+// SignMessage sign an arbitrary message, such as login challenge, with domain separated prefix.
+// The signature can be verified by VerifyMessage.
+func (a *Account) SignMessage(msg []byte) ([]byte, error) {
+	if a.IsWatchOnly() {
+		return nil, common.ErrWatchOnlyAccount
+	}
+
+	cryptoClient := crypto.GetCryptoClient()
+	privateKey, err := cryptoClient.GetEcdsaPrivateKeyFromJsonStr(a.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	return cryptoClient.SignECDSA(privateKey, MessageHash(msg))
+}
+
+// VerifyMessage verify message signature created by SignMessage, returns nil if the signature is valid.
+//
+// Parameters:
+//   - `address`：  签名者地址，必须与公钥匹配。
+//   - `publicKey`：JSON 格式的公钥。
+//   - `msg`：      消息原文。
+//   - `sign`：     签名。
+func VerifyMessage(address, publicKey string, msg, sign []byte) error {
+	cryptoClient := crypto.GetCryptoClient()
+	ecdsaPublicKey, err := cryptoClient.GetEcdsaPublicKeyFromJsonStr(publicKey)
+	if err != nil {
+		return err
+	}
+	if ok, _ := cryptoClient.VerifyAddressUsingPublicKey(address, ecdsaPublicKey); !ok {
+		return errors.Wrap(common.ErrInvalidAccount, "address and public key not match")
+	}
+
+	ok, err := cryptoClient.VerifyECDSA(ecdsaPublicKey, sign, MessageHash(msg))
+	if err != nil || !ok {
+		return common.ErrInvalidSignature
+	}
+	return nil
+}
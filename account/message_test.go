@@ -0,0 +1,51 @@
+package account
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/superconsensus/matrix-sdk-go/v2/common"
+	"github.com/superconsensus/matrix-sdk-go/v2/common/config"
+)
+
+func TestSignMessage(t *testing.T) {
+	cfg := config.GetInstance()
+	defer cfg.SetXchainCrypto()
+
+	suites := []func(){cfg.SetXchainCrypto, cfg.SetGMCrypto}
+	for _, setSuite := range suites {
+		setSuite()
+
+		acc, _ := CreateAccount(1, 1)
+		other, _ := CreateAccount(1, 1)
+		msg := []byte("login challenge 123")
+
+		sign, err := acc.SignMessage(msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cases := []struct {
+			address   string
+			publicKey string
+			msg       []byte
+			err       error
+		}{
+			{address: acc.Address, publicKey: acc.PublicKey, msg: msg},
+			{address: acc.Address, publicKey: acc.PublicKey, msg: []byte("login challenge 124"), err: common.ErrInvalidSignature},
+			{address: other.Address, publicKey: other.PublicKey, msg: msg, err: common.ErrInvalidSignature},
+			{address: other.Address, publicKey: acc.PublicKey, msg: msg, err: common.ErrInvalidAccount},
+		}
+		for i, c := range cases {
+			err := VerifyMessage(c.address, c.publicKey, c.msg, sign)
+			if !errors.Is(err, c.err) {
+				t.Errorf("VerifyMessage %s case %d expect %v, got %v", cfg.Crypto, i, c.err, err)
+			}
+		}
+	}
+
+	watchAcc := &Account{Address: "a"}
+	if _, err := watchAcc.SignMessage([]byte("a")); !errors.Is(err, common.ErrWatchOnlyAccount) {
+		t.Error("SignMessage watch only account assert failed", err)
+	}
+}
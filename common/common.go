@@ -45,13 +45,13 @@ func SetSeed() error {
 	return nil
 }
 
-// PathExistsAndMkdir judge whether path is existant or not
+// PathExistsAndMkdir judge whether path is existant or not, create it and all parents if not.
 func PathExistsAndMkdir(path string) error {
 	_, err := os.Stat(path)
 	if err == nil {
 		return nil
 	}
-	err = os.Mkdir(path, os.ModePerm)
+	err = os.MkdirAll(path, os.ModePerm)
 	if err != nil {
 		return err
 	}
@@ -14,12 +14,48 @@ var (
 	ErrTxNotFound = errors.New("tx not found")
 	// ErrInvalidAccount invalid account
 	ErrInvalidAccount = errors.New("invalid account")
+	// ErrInvalidAddress address is neither AK address, contract account nor contract name
+	ErrInvalidAddress = errors.New("invalid address")
 	// ErrInvalidContractAccount contract account invalid
 	ErrInvalidContractAccount = errors.New("conrtact account must be numbers of length 16")
+	// ErrContractAccountNotFound address not belongs to any contract account
+	ErrContractAccountNotFound = errors.New("contract account not found")
+	// ErrInvalidACL ACL can not be accepted by chain
+	ErrInvalidACL = errors.New("invalid ACL")
+	// ErrAuthRequireNotSatisfied transaction signatures can not satisfy AuthRequire
+	ErrAuthRequireNotSatisfied = errors.New("AuthRequire not satisfied")
+	// ErrUnknownEvent no decoder registered for the contract event
+	ErrUnknownEvent = errors.New("unknown contract event")
+	// ErrTxIDMismatch recomputed txid is different from the transaction's
+	ErrTxIDMismatch = errors.New("txid mismatch")
+	// ErrMerkleRootMismatch recomputed merkle root is different from the block's
+	ErrMerkleRootMismatch = errors.New("merkle root mismatch")
+	// ErrBlockIDMismatch recomputed block id is different from the block's
+	ErrBlockIDMismatch = errors.New("block id mismatch")
+	// ErrTxNotInBlock transaction is not included in the block
+	ErrTxNotInBlock = errors.New("tx not in block")
+	// ErrHeaderNotLinked block does not link to the verified header chain
+	ErrHeaderNotLinked = errors.New("block not linked to verified headers")
+	// ErrBlockReorganized block got before is no longer in the trunk
+	ErrBlockReorganized = errors.New("block reorganized out of trunk")
+	// ErrEventStreamClosed event stream closed by the node
+	ErrEventStreamClosed = errors.New("event stream closed")
 	// ErrAmountNotEnough amount invalid
 	ErrAmountNotEnough = errors.New("Amount must be bigger than compliancecheck fee which is 10")
 	//ErrInvalidInitiator from account invalid
 	ErrInvalidInitiator = errors.New("From account can not be nil")
 	// ErrInvalidParam param invalid
 	ErrInvalidParam = errors.New("Parmeter invalid")
+	// ErrAccountNotFound account not found in keystore
+	ErrAccountNotFound = errors.New("account not found in keystore")
+	// ErrAccountExists account already exists in keystore
+	ErrAccountExists = errors.New("account already exists in keystore")
+	// ErrLabelExists label already used by another account in keystore
+	ErrLabelExists = errors.New("label already used in keystore")
+	// ErrWatchOnlyAccount account has no private key
+	ErrWatchOnlyAccount = errors.New("account has no private key, it is watch only")
+	// ErrInvalidSignature signature verify failed
+	ErrInvalidSignature = errors.New("invalid signature")
+	// ErrInvalidPassword wrong password or corrupted key file
+	ErrInvalidPassword = errors.New("invalid password or corrupted key file")
 )
@@ -10,6 +10,7 @@ require (
 	github.com/valyala/fasthttp v1.35.0
 	github.com/xuperchain/crypto v0.0.0-20211221122406-302ac826ac90
 	github.com/xuperchain/xuperchain v0.0.0-20210708031936-951e4ade7bdd
+	golang.org/x/crypto v0.0.0-20220214200702-86341886e292
 	google.golang.org/grpc v1.33.1
 	gopkg.in/yaml.v2 v2.3.0
 )
@@ -0,0 +1,238 @@
+// Package indexer index transfers, contract events and contract invocations of a chain into a Store.
+//
+// Indexer backfills from the store checkpoint, then follows new blocks, orphaned blocks are reverted from the store.
+// MemoryStore and LogStore keep all indexed data in memory and are for small datasets only,
+// implement Store over a database for a production indexer.
+package indexer
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/xuperchain/xuperchain/service/pb"
+
+	"github.com/superconsensus/matrix-sdk-go/v2/common"
+	"github.com/superconsensus/matrix-sdk-go/v2/xuper"
+)
+
+// Client the methods of xuper.XClient used by Indexer.
+type Client interface {
+	WatchChainEvents(ctx context.Context, opts ...xuper.BlockEventOption) (*xuper.ChainWatcher, error)
+	FetchFullBlock(ctx context.Context, block *xuper.FilteredBlock, workers int) (*xuper.FullBlock, error)
+	QueryBlockByHeight(height int64, opts ...xuper.QueryOption) (*pb.Block, error)
+}
+
+// Indexer chain indexer.
+type Indexer struct {
+	client Client
+	store  Store
+	opt    *options
+}
+
+// Option indexer option.
+type Option func(*options)
+
+type options struct {
+	bcname            string
+	startHeight       int64
+	confirmationDepth int64
+	txFetchWorkers    int
+}
+
+// WithBcname blockchain name, default xuper.
+func WithBcname(name string) Option {
+	return func(o *options) {
+		o.bcname = name
+	}
+}
+
+// WithStartHeight the first block to index if the store is empty, default 0.
+func WithStartHeight(height int64) Option {
+	return func(o *options) {
+		o.startHeight = height
+	}
+}
+
+// WithConfirmationDepth reorg detection depth, default 3.
+func WithConfirmationDepth(depth int64) Option {
+	return func(o *options) {
+		o.confirmationDepth = depth
+	}
+}
+
+// WithTxFetchWorkers max concurrent QueryTxByID, default 8.
+func WithTxFetchWorkers(n int) Option {
+	return func(o *options) {
+		o.txFetchWorkers = n
+	}
+}
+
+// New new indexer.
+//
+// Parameters:
+//   - `client`: xuper.XClient.
+//   - `store` : Such as NewMemoryStore or NewLogStore.
+func New(client Client, store Store, opts ...Option) *Indexer {
+	opt := &options{
+		bcname:            "xuper",
+		confirmationDepth: 3,
+		txFetchWorkers:    8,
+	}
+	for _, o := range opts {
+		o(opt)
+	}
+	return &Indexer{client: client, store: store, opt: opt}
+}
+
+// Run index until ctx done or error, it returns ctx.Err() when ctx done and ErrEventStreamClosed
+// if the node closes the event stream.
+func (i *Indexer) Run(ctx context.Context) error {
+	start, err := i.resume()
+	if err != nil {
+		return err
+	}
+
+	watcher, err := i.client.WatchChainEvents(ctx,
+		xuper.WithBlockEventBcname(i.opt.bcname),
+		xuper.WithConfirmationDepth(i.opt.confirmationDepth),
+		xuper.WithBlockRange(strconv.FormatInt(start, 10), ""))
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	for event := range watcher.C {
+		switch event.Type {
+		case xuper.ChainEventBlockAdded:
+			fullBlock, err := i.client.FetchFullBlock(ctx, event.Block, i.opt.txFetchWorkers)
+			if err != nil {
+				return err
+			}
+			if err := i.store.SaveBlock(NewBlock(fullBlock)); err != nil {
+				return errors.Wrapf(err, "save block %d failed", event.Block.BlockHeight)
+			}
+		case xuper.ChainEventBlockReverted:
+			if err := i.store.RevertFrom(event.Block.BlockHeight); err != nil {
+				return errors.Wrapf(err, "revert block %d failed", event.Block.BlockHeight)
+			}
+		case xuper.ChainEventBlockConfirmed:
+		default:
+			return event.Err
+		}
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return common.ErrEventStreamClosed
+}
+
+// resume returns the height to index from, blocks saved before which are orphaned now are reverted.
+func (i *Indexer) resume() (int64, error) {
+	cp, err := i.store.Checkpoint()
+	if err != nil {
+		return 0, err
+	}
+	if cp == nil {
+		return i.opt.startHeight, nil
+	}
+
+	height := cp.Height
+	for ; height >= i.opt.startHeight; height-- {
+		saved, err := i.store.BlockID(height)
+		if err != nil {
+			return 0, err
+		}
+		if saved == "" {
+			break
+		}
+		block, err := i.client.QueryBlockByHeight(height, xuper.WithQueryBcname(i.opt.bcname))
+		if err != nil {
+			return 0, errors.Wrapf(err, "query block %d failed", height)
+		}
+		if hex.EncodeToString(block.GetBlock().GetBlockid()) == saved {
+			break
+		}
+	}
+	if height < cp.Height {
+		if err := i.store.RevertFrom(height + 1); err != nil {
+			return 0, err
+		}
+	}
+	return height + 1, nil
+}
+
+// NewBlock extract transfers, contract events and contract invocations of the block.
+func NewBlock(fullBlock *xuper.FullBlock) *Block {
+	block := &Block{
+		Height:  fullBlock.BlockHeight,
+		Blockid: fullBlock.Blockid,
+	}
+	for _, tx := range fullBlock.Txs {
+		block.Transfers = append(block.Transfers, newTransfers(fullBlock.BlockHeight, tx)...)
+		for _, event := range tx.Events {
+			block.Events = append(block.Events, &Event{
+				Txid:     tx.Txid,
+				Height:   fullBlock.BlockHeight,
+				Contract: event.Contract,
+				Name:     event.Name,
+				Body:     []byte(event.Body),
+			})
+		}
+		for _, req := range tx.ContractRequests {
+			block.Invocations = append(block.Invocations, &Invocation{
+				Txid:         tx.Txid,
+				Height:       fullBlock.BlockHeight,
+				Initiator:    tx.Initiator,
+				ModuleName:   req.ModuleName,
+				ContractName: req.ContractName,
+				MethodName:   req.MethodName,
+				Amount:       req.Amount,
+			})
+		}
+	}
+	return block
+}
+
+// newTransfers outputs to addresses which are not inputs of the transaction, the sender is the first input address.
+// Outputs to the fee address are summed as the fee of each transfer, the same as xuper.QueryAddressTxs.
+func newTransfers(height int64, tx *xuper.DecodedTx) []*Transfer {
+	from := tx.Initiator
+	senders := map[string]bool{}
+	for i, input := range tx.Inputs {
+		if i == 0 {
+			from = input.FromAddr
+		}
+		senders[input.FromAddr] = true
+	}
+	fee := big.NewInt(0)
+	for _, output := range tx.Outputs {
+		if output.ToAddr == feeAddress {
+			fee.Add(fee, output.Amount)
+		}
+	}
+
+	transfers := []*Transfer{}
+	for _, output := range tx.Outputs {
+		if senders[output.ToAddr] || output.ToAddr == feeAddress || output.Amount.Sign() == 0 {
+			continue
+		}
+		transfer := &Transfer{
+			Txid:      tx.Txid,
+			Height:    height,
+			Timestamp: tx.Timestamp,
+			From:      from,
+			To:        output.ToAddr,
+			Amount:    new(big.Int).Set(output.Amount),
+			Fee:       new(big.Int).Set(fee),
+			Coinbase:  tx.Coinbase,
+		}
+		if tx.Coinbase {
+			transfer.From = ""
+		}
+		transfers = append(transfers, transfer)
+	}
+	return transfers
+}
@@ -0,0 +1,124 @@
+package indexer
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/xuperchain/xuperchain/service/pb"
+
+	"github.com/superconsensus/matrix-sdk-go/v2/common"
+	"github.com/superconsensus/matrix-sdk-go/v2/xuper"
+)
+
+type mockClient struct {
+	events    []*xuper.ChainEvent
+	blocks    map[string]*xuper.FullBlock
+	canonical map[int64]string
+}
+
+func (m *mockClient) WatchChainEvents(ctx context.Context, opts ...xuper.BlockEventOption) (*xuper.ChainWatcher, error) {
+	c := make(chan *xuper.ChainEvent, len(m.events))
+	for _, event := range m.events {
+		c <- event
+	}
+	close(c)
+	return &xuper.ChainWatcher{C: c}, nil
+}
+
+func (m *mockClient) FetchFullBlock(ctx context.Context, block *xuper.FilteredBlock, workers int) (*xuper.FullBlock, error) {
+	return m.blocks[block.Blockid], nil
+}
+
+func (m *mockClient) QueryBlockByHeight(height int64, opts ...xuper.QueryOption) (*pb.Block, error) {
+	id, _ := hex.DecodeString(m.canonical[height])
+	return &pb.Block{Blockid: id, Block: &pb.InternalBlock{Blockid: id, Height: height}}, nil
+}
+
+func newTransferTx(txid, from, to string, amount int64) *xuper.DecodedTx {
+	return &xuper.DecodedTx{
+		Txid:      txid,
+		Initiator: from,
+		Inputs:    []*xuper.DecodedTxInput{{FromAddr: from, Amount: big.NewInt(amount + 1)}},
+		Outputs: []*xuper.DecodedTxOutput{
+			{ToAddr: to, Amount: big.NewInt(amount)},
+			{ToAddr: from, Amount: big.NewInt(1)},
+		},
+	}
+}
+
+func TestIndexerRun(t *testing.T) {
+	invokeTx := newTransferTx("t3", "alice", "carol", 5)
+	invokeTx.ContractRequests = []*xuper.DecodedContractRequest{{ModuleName: "wasm", ContractName: "counter", MethodName: "increase"}}
+	invokeTx.Events = []*xuper.ContractEvent{{Contract: "counter", Name: "increase", Body: `{"n":1}`}}
+
+	feeTx := newTransferTx("t2", "alice", "bob", 20)
+	feeTx.Outputs = append(feeTx.Outputs, &xuper.DecodedTxOutput{ToAddr: "$", Amount: big.NewInt(2)})
+
+	client := &mockClient{
+		blocks: map[string]*xuper.FullBlock{
+			"a1": {BlockHeight: 1, Blockid: "a1", Txs: []*xuper.DecodedTx{
+				{Txid: "t0", Coinbase: true, Outputs: []*xuper.DecodedTxOutput{{ToAddr: "alice", Amount: big.NewInt(100)}}},
+			}},
+			"a2": {BlockHeight: 2, Blockid: "a2", Txs: []*xuper.DecodedTx{newTransferTx("t1", "alice", "bob", 10)}},
+			"b2": {BlockHeight: 2, Blockid: "b2", Txs: []*xuper.DecodedTx{feeTx, invokeTx}},
+		},
+		canonical: map[int64]string{1: "a1", 2: "b2"},
+	}
+	for _, e := range []struct{ typ, id string }{
+		{xuper.ChainEventBlockAdded, "a1"},
+		{xuper.ChainEventBlockAdded, "a2"},
+		{xuper.ChainEventBlockReverted, "a2"},
+		{xuper.ChainEventBlockAdded, "b2"},
+		{xuper.ChainEventBlockConfirmed, "a1"},
+	} {
+		block := client.blocks[e.id]
+		client.events = append(client.events, &xuper.ChainEvent{
+			Type:  e.typ,
+			Block: &xuper.FilteredBlock{Blockid: block.Blockid, BlockHeight: block.BlockHeight},
+		})
+	}
+
+	// the mock stream ends after the events while ctx is live.
+	store := NewMemoryStore()
+	if err := New(client, store, WithStartHeight(1)).Run(context.Background()); !errors.Is(err, common.ErrEventStreamClosed) {
+		t.Fatal("Indexer stream closed assert failed", err)
+	}
+
+	cp, _ := store.Checkpoint()
+	if cp == nil || cp.Height != 2 || cp.Blockid != "b2" {
+		t.Error("Indexer checkpoint assert failed", cp)
+	}
+	transfers, _ := store.Transfers("bob")
+	if len(transfers) != 1 || transfers[0].Txid != "t2" || transfers[0].From != "alice" || transfers[0].Amount.Int64() != 20 || transfers[0].Fee.Int64() != 2 {
+		t.Error("Indexer transfers assert failed", transfers)
+	}
+	if transfers, _ := store.Transfers("$"); len(transfers) != 0 {
+		t.Error("Indexer fee transfers assert failed", transfers)
+	}
+	transfers, _ = store.Transfers("alice")
+	if len(transfers) != 3 || !transfers[0].Coinbase || transfers[0].From != "" {
+		t.Error("Indexer coinbase transfers assert failed", transfers)
+	}
+	events, _ := store.Events("counter")
+	invocations, _ := store.Invocations("counter")
+	if len(events) != 1 || string(events[0].Body) != `{"n":1}` || len(invocations) != 1 || invocations[0].Initiator != "alice" {
+		t.Error("Indexer contract assert failed", events, invocations)
+	}
+
+	// the node switched to another branch at height 2 while indexer stopped.
+	client.canonical[2] = "c2"
+	indexer := New(client, store, WithStartHeight(1))
+	start, err := indexer.resume()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cp, _ := store.Checkpoint(); start != 2 || cp.Height != 1 {
+		t.Error("Indexer resume assert failed", start, cp)
+	}
+	if transfers, _ := store.Transfers("bob"); len(transfers) != 0 {
+		t.Error("Indexer resume revert assert failed", transfers)
+	}
+}
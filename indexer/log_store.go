@@ -0,0 +1,120 @@
+package indexer
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// LogStore MemoryStore persisted as an append only JSON lines log of saves and reverts,
+// the log is replayed completely when opened. It is not an on-disk index, all indexed data stays in memory
+// and the log is never compacted, so it is for small datasets only.
+type LogStore struct {
+	*MemoryStore
+
+	mu   sync.Mutex
+	file *os.File
+}
+
+type fileRecord struct {
+	Save   *Block `json:"save,omitempty"`
+	Revert *int64 `json:"revert,omitempty"`
+}
+
+// NewLogStore open or create the log store.
+//
+// Parameters:
+//   - `path`: The log file path, its directory must exist.
+func NewLogStore(path string) (*LogStore, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &LogStore{MemoryStore: NewMemoryStore(), file: file}
+	if err := s.load(); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// load replay the log, an incomplete last record written before crash is dropped.
+func (s *LogStore) load() error {
+	reader := bufio.NewReader(s.file)
+	var offset int64
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			if len(line) > 0 {
+				return s.file.Truncate(offset)
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		record := &fileRecord{}
+		if err := json.Unmarshal(line, record); err != nil {
+			return errors.Wrapf(err, "corrupted index log at offset %d", offset)
+		}
+		if err := s.apply(record); err != nil {
+			return err
+		}
+		offset += int64(len(line))
+	}
+}
+
+func (s *LogStore) apply(record *fileRecord) error {
+	switch {
+	case record.Save != nil:
+		return s.MemoryStore.SaveBlock(record.Save)
+	case record.Revert != nil:
+		return s.MemoryStore.RevertFrom(*record.Revert)
+	default:
+		return errors.New("empty index log record")
+	}
+}
+
+func (s *LogStore) append(record *fileRecord) error {
+	buf, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.file.Seek(0, io.SeekEnd); err != nil {
+		return err
+	}
+	if _, err := s.file.Write(append(buf, '\n')); err != nil {
+		return err
+	}
+	if err := s.file.Sync(); err != nil {
+		return err
+	}
+	return s.apply(record)
+}
+
+// SaveBlock implements Store.
+func (s *LogStore) SaveBlock(block *Block) error {
+	if cp, _ := s.Checkpoint(); cp != nil && block.Height <= cp.Height {
+		return errors.Errorf("block %d is not above checkpoint %d", block.Height, cp.Height)
+	}
+	return s.append(&fileRecord{Save: block})
+}
+
+// RevertFrom implements Store.
+func (s *LogStore) RevertFrom(height int64) error {
+	return s.append(&fileRecord{Revert: &height})
+}
+
+// Close implements Store.
+func (s *LogStore) Close() error {
+	return s.file.Close()
+}
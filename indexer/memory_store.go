@@ -0,0 +1,160 @@
+package indexer
+
+import (
+	"fmt"
+	"sync"
+)
+
+// MemoryStore Store in memory.
+type MemoryStore struct {
+	mu sync.RWMutex
+
+	blocks      []*Block
+	transfers   map[string][]*Transfer
+	events      map[string][]*Event
+	invocations map[string][]*Invocation
+}
+
+// NewMemoryStore new memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		transfers:   map[string][]*Transfer{},
+		events:      map[string][]*Event{},
+		invocations: map[string][]*Invocation{},
+	}
+}
+
+// Checkpoint implements Store.
+func (s *MemoryStore) Checkpoint() (*Checkpoint, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if len(s.blocks) == 0 {
+		return nil, nil
+	}
+	tip := s.blocks[len(s.blocks)-1]
+	return &Checkpoint{Height: tip.Height, Blockid: tip.Blockid}, nil
+}
+
+// BlockID implements Store.
+func (s *MemoryStore) BlockID(height int64) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if i := s.search(height); i < len(s.blocks) && s.blocks[i].Height == height {
+		return s.blocks[i].Blockid, nil
+	}
+	return "", nil
+}
+
+// SaveBlock implements Store.
+func (s *MemoryStore) SaveBlock(block *Block) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.blocks) > 0 && block.Height <= s.blocks[len(s.blocks)-1].Height {
+		return fmt.Errorf("block %d is not above checkpoint %d", block.Height, s.blocks[len(s.blocks)-1].Height)
+	}
+
+	s.blocks = append(s.blocks, block)
+	for _, transfer := range block.Transfers {
+		s.transfers[transfer.To] = append(s.transfers[transfer.To], transfer)
+		if transfer.From != "" && transfer.From != transfer.To {
+			s.transfers[transfer.From] = append(s.transfers[transfer.From], transfer)
+		}
+	}
+	for _, event := range block.Events {
+		s.events[event.Contract] = append(s.events[event.Contract], event)
+	}
+	for _, invocation := range block.Invocations {
+		s.invocations[invocation.ContractName] = append(s.invocations[invocation.ContractName], invocation)
+	}
+	return nil
+}
+
+// RevertFrom implements Store.
+func (s *MemoryStore) RevertFrom(height int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.search(height)
+	for _, block := range s.blocks[i:] {
+		for _, transfer := range block.Transfers {
+			s.transfers[transfer.To] = trimTransfers(s.transfers[transfer.To], height)
+			s.transfers[transfer.From] = trimTransfers(s.transfers[transfer.From], height)
+		}
+		for _, event := range block.Events {
+			s.events[event.Contract] = trimEvents(s.events[event.Contract], height)
+		}
+		for _, invocation := range block.Invocations {
+			s.invocations[invocation.ContractName] = trimInvocations(s.invocations[invocation.ContractName], height)
+		}
+	}
+	s.blocks = s.blocks[:i]
+	return nil
+}
+
+// Transfers implements Store.
+func (s *MemoryStore) Transfers(address string) ([]*Transfer, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]*Transfer{}, s.transfers[address]...), nil
+}
+
+// Events implements Store.
+func (s *MemoryStore) Events(contract string) ([]*Event, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]*Event{}, s.events[contract]...), nil
+}
+
+// Invocations implements Store.
+func (s *MemoryStore) Invocations(contract string) ([]*Invocation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]*Invocation{}, s.invocations[contract]...), nil
+}
+
+// Close implements Store.
+func (s *MemoryStore) Close() error {
+	return nil
+}
+
+// search returns index of the first block whose height >= height.
+func (s *MemoryStore) search(height int64) int {
+	lo, hi := 0, len(s.blocks)
+	for lo < hi {
+		mid := (lo + hi) / 2
+		if s.blocks[mid].Height < height {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo
+}
+
+// records are appended by ascending height, so reverted ones are at the tail.
+func trimTransfers(transfers []*Transfer, height int64) []*Transfer {
+	n := len(transfers)
+	for n > 0 && transfers[n-1].Height >= height {
+		n--
+	}
+	return transfers[:n]
+}
+
+func trimEvents(events []*Event, height int64) []*Event {
+	n := len(events)
+	for n > 0 && events[n-1].Height >= height {
+		n--
+	}
+	return events[:n]
+}
+
+func trimInvocations(invocations []*Invocation, height int64) []*Invocation {
+	n := len(invocations)
+	for n > 0 && invocations[n-1].Height >= height {
+		n--
+	}
+	return invocations[:n]
+}
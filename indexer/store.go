@@ -0,0 +1,77 @@
+package indexer
+
+import (
+	"math/big"
+)
+
+// Checkpoint the last indexed block.
+type Checkpoint struct {
+	Height  int64  `json:"height"`
+	Blockid string `json:"blockid"`
+}
+
+// feeAddress outputs to it are transaction fee.
+const feeAddress = "$"
+
+// Transfer value moved by a transaction output, change and fee outputs are not included.
+type Transfer struct {
+	Txid      string   `json:"txid"`
+	Height    int64    `json:"height"`
+	Timestamp int64    `json:"timestamp"`
+	From      string   `json:"from"`
+	To        string   `json:"to"`
+	Amount    *big.Int `json:"amount"`
+	// Fee paid by the transaction, the same for all transfers of one transaction.
+	Fee      *big.Int `json:"fee,omitempty"`
+	Coinbase bool     `json:"coinbase,omitempty"`
+}
+
+// Event contract event.
+type Event struct {
+	Txid     string `json:"txid"`
+	Height   int64  `json:"height"`
+	Contract string `json:"contract"`
+	Name     string `json:"name"`
+	Body     []byte `json:"body"`
+}
+
+// Invocation contract invoke.
+type Invocation struct {
+	Txid         string   `json:"txid"`
+	Height       int64    `json:"height"`
+	Initiator    string   `json:"initiator"`
+	ModuleName   string   `json:"module_name"`
+	ContractName string   `json:"contract_name"`
+	MethodName   string   `json:"method_name"`
+	Amount       *big.Int `json:"amount,omitempty"`
+}
+
+// Block indexed data of a block.
+type Block struct {
+	Height      int64         `json:"height"`
+	Blockid     string        `json:"blockid"`
+	Transfers   []*Transfer   `json:"transfers,omitempty"`
+	Events      []*Event      `json:"events,omitempty"`
+	Invocations []*Invocation `json:"invocations,omitempty"`
+}
+
+// Store indexed data storage, blocks are saved by ascending height.
+type Store interface {
+	// Checkpoint returns the last saved block, nil if nothing saved.
+	Checkpoint() (*Checkpoint, error)
+	// BlockID returns the saved block id of height, empty if not saved.
+	BlockID(height int64) (string, error)
+	// SaveBlock save the block and advance the checkpoint, height must be bigger than the checkpoint.
+	SaveBlock(block *Block) error
+	// RevertFrom delete blocks whose height >= height.
+	RevertFrom(height int64) error
+
+	// Transfers returns transfers from or to the address by ascending height.
+	Transfers(address string) ([]*Transfer, error)
+	// Events returns events of the contract by ascending height.
+	Events(contract string) ([]*Event, error)
+	// Invocations returns invocations of the contract by ascending height.
+	Invocations(contract string) ([]*Invocation, error)
+
+	Close() error
+}
@@ -0,0 +1,92 @@
+package indexer
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func testStore(t *testing.T, store Store) {
+	blocks := []*Block{
+		{Height: 1, Blockid: "a1", Transfers: []*Transfer{{Txid: "t1", Height: 1, From: "alice", To: "bob", Amount: big.NewInt(1)}}},
+		{Height: 2, Blockid: "a2", Events: []*Event{{Txid: "t2", Height: 2, Contract: "counter", Name: "increase"}}},
+		{Height: 3, Blockid: "a3", Transfers: []*Transfer{{Txid: "t3", Height: 3, From: "bob", To: "carol", Amount: big.NewInt(2)}}},
+	}
+	for _, block := range blocks {
+		if err := store.SaveBlock(block); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.SaveBlock(&Block{Height: 2, Blockid: "b2"}); err == nil {
+		t.Error("SaveBlock below checkpoint assert failed")
+	}
+	if transfers, _ := store.Transfers("bob"); len(transfers) != 2 {
+		t.Error("Transfers assert failed", transfers)
+	}
+
+	if err := store.RevertFrom(2); err != nil {
+		t.Fatal(err)
+	}
+	cp, _ := store.Checkpoint()
+	if cp == nil || cp.Height != 1 || cp.Blockid != "a1" {
+		t.Error("RevertFrom checkpoint assert failed", cp)
+	}
+	if transfers, _ := store.Transfers("bob"); len(transfers) != 1 || transfers[0].Txid != "t1" {
+		t.Error("RevertFrom transfers assert failed", transfers)
+	}
+	if events, _ := store.Events("counter"); len(events) != 0 {
+		t.Error("RevertFrom events assert failed", events)
+	}
+	if id, _ := store.BlockID(1); id != "a1" {
+		t.Error("BlockID assert failed", id)
+	}
+	if id, _ := store.BlockID(2); id != "" {
+		t.Error("BlockID reverted assert failed", id)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore()
+	if cp, err := store.Checkpoint(); cp != nil || err != nil {
+		t.Error("empty MemoryStore checkpoint assert failed", cp, err)
+	}
+	testStore(t, store)
+}
+
+func TestLogStore(t *testing.T) {
+	dir := "indexer_test_data"
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "index.log")
+
+	store, err := NewLogStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testStore(t, store)
+	store.Close()
+
+	// incomplete record written before crash.
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	f.WriteString(`{"save":{"height":4`)
+	f.Close()
+
+	store, err = NewLogStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	cp, _ := store.Checkpoint()
+	if cp == nil || cp.Height != 1 {
+		t.Error("LogStore reopen checkpoint assert failed", cp)
+	}
+	if transfers, _ := store.Transfers("bob"); len(transfers) != 1 || transfers[0].Amount.Int64() != 1 {
+		t.Error("LogStore reopen transfers assert failed", transfers)
+	}
+	if err := store.SaveBlock(&Block{Height: 2, Blockid: "b2"}); err != nil {
+		t.Error("LogStore save after reopen assert failed", err)
+	}
+}
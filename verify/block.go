@@ -0,0 +1,175 @@
+// Package verify recompute txids, merkle roots and block ids to check data returned by a node
+// instead of trusting it.
+package verify
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/xuperchain/xuperchain/service/pb"
+
+	"github.com/superconsensus/matrix-sdk-go/v2/common"
+	"github.com/superconsensus/matrix-sdk-go/v2/xuper"
+)
+
+// Client the methods of xuper.XClient used by VerifyTxOnChain.
+type Client interface {
+	QueryTxByID(txID string, opts ...xuper.QueryOption) (*pb.Transaction, error)
+	QueryBlockByID(blockID string, opts ...xuper.QueryOption) (*pb.Block, error)
+}
+
+// MakeBlockID compute the block id the same as the chain ledger.
+func MakeBlockID(block *pb.InternalBlock) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	fields := []interface{}{block.Version, block.Nonce, block.TxCount}
+	if block.Proposer != nil {
+		fields = append(fields, block.Proposer)
+	}
+	fields = append(fields, block.Timestamp)
+	if block.Pubkey != nil {
+		fields = append(fields, block.Pubkey)
+	}
+	fields = append(fields, block.PreHash, block.MerkleRoot)
+	if err := writeFields(buf, fields...); err != nil {
+		return nil, err
+	}
+
+	// failed txs in txid ascii order.
+	txids := make([]string, 0, len(block.FailedTxs))
+	for txid := range block.FailedTxs {
+		txids = append(txids, txid)
+	}
+	sort.Strings(txids)
+	for _, txid := range txids {
+		if err := writeFields(buf, []byte(block.FailedTxs[txid])); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writeFields(buf, block.CurTerm, block.CurBlockNum); err != nil {
+		return nil, err
+	}
+	if block.TargetBits > 0 {
+		if err := writeFields(buf, block.TargetBits); err != nil {
+			return nil, err
+		}
+	}
+	if err := writeJustify(buf, block.Justify); err != nil {
+		return nil, err
+	}
+
+	first := sha256.Sum256(buf.Bytes())
+	second := sha256.Sum256(first[:])
+	return second[:], nil
+}
+
+// VerifyBlockID recompute the block id and compare with block.Blockid.
+func VerifyBlockID(block *pb.InternalBlock) error {
+	id, err := MakeBlockID(block)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(id, block.GetBlockid()) {
+		return errors.Wrapf(common.ErrBlockIDMismatch, "expect %x, got %x", block.GetBlockid(), id)
+	}
+	return nil
+}
+
+// VerifyTxID recompute the txid with common.MakeTransactionID and compare with tx.Txid.
+func VerifyTxID(tx *pb.Transaction) error {
+	id, err := common.MakeTransactionID(tx)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(id, tx.GetTxid()) {
+		return errors.Wrapf(common.ErrTxIDMismatch, "expect %x, got %x", tx.GetTxid(), id)
+	}
+	return nil
+}
+
+// VerifyBlock verify txids of all transactions, the merkle root and the block id.
+func VerifyBlock(block *pb.InternalBlock) error {
+	if block == nil {
+		return errors.New("block can not be nil")
+	}
+	for _, tx := range block.GetTransactions() {
+		if err := VerifyTxID(tx); err != nil {
+			return err
+		}
+	}
+	if err := VerifyMerkleRoot(block); err != nil {
+		return err
+	}
+	return VerifyBlockID(block)
+}
+
+// VerifyTxInBlock verify the transaction txid, the block, and the transaction is included in the block.
+func VerifyTxInBlock(tx *pb.Transaction, block *pb.InternalBlock) error {
+	if tx == nil {
+		return errors.New("tx can not be nil")
+	}
+	if err := VerifyTxID(tx); err != nil {
+		return err
+	}
+	if err := VerifyBlock(block); err != nil {
+		return err
+	}
+
+	proof, err := NewMerkleProof(block, tx.GetTxid())
+	if err != nil {
+		return err
+	}
+	return proof.Verify(block.GetMerkleRoot())
+}
+
+// VerifyTxOnChain query the transaction and its block, returns them if VerifyTxInBlock passes.
+//
+// Parameters:
+//   - `client`: xuper.XClient.
+//   - `txid`  : Hex txid.
+func VerifyTxOnChain(client Client, txid string, opts ...xuper.QueryOption) (*pb.Transaction, *pb.InternalBlock, error) {
+	tx, err := client.QueryTxByID(txid, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	if hex.EncodeToString(tx.GetTxid()) != txid {
+		return nil, nil, errors.Wrapf(common.ErrTxIDMismatch, "query %s, got %x", txid, tx.GetTxid())
+	}
+
+	block, err := client.QueryBlockByID(hex.EncodeToString(tx.GetBlockid()), opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := VerifyTxInBlock(tx, block.GetBlock()); err != nil {
+		return nil, nil, err
+	}
+	return tx, block.GetBlock(), nil
+}
+
+func writeJustify(buf *bytes.Buffer, justify *pb.QuorumCert) error {
+	if justify == nil {
+		return nil
+	}
+	if err := writeFields(buf, justify.ProposalId, justify.ProposalMsg, justify.Type, justify.ViewNumber); err != nil {
+		return err
+	}
+	for _, sign := range justify.GetSignInfos().GetQCSignInfos() {
+		if err := writeFields(buf, []byte(sign.Address), []byte(sign.PublicKey), sign.Sign); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeFields(buf *bytes.Buffer, fields ...interface{}) error {
+	for _, field := range fields {
+		if err := binary.Write(buf, binary.LittleEndian, field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
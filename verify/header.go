@@ -0,0 +1,252 @@
+package verify
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/xuperchain/xuperchain/service/pb"
+
+	"github.com/superconsensus/matrix-sdk-go/v2/common"
+	"github.com/superconsensus/matrix-sdk-go/v2/crypto"
+	"github.com/superconsensus/matrix-sdk-go/v2/xuper"
+)
+
+// Header verified block header.
+type Header struct {
+	Height     int64
+	Blockid    []byte
+	PreHash    []byte
+	MerkleRoot []byte
+	Proposer   string
+	Timestamp  int64
+}
+
+// NewHeader header of the block.
+func NewHeader(block *pb.InternalBlock) *Header {
+	return &Header{
+		Height:     block.GetHeight(),
+		Blockid:    block.GetBlockid(),
+		PreHash:    block.GetPreHash(),
+		MerkleRoot: block.GetMerkleRoot(),
+		Proposer:   string(block.GetProposer()),
+		Timestamp:  block.GetTimestamp(),
+	}
+}
+
+// HeaderStore verified headers storage.
+type HeaderStore interface {
+	// Put save the header, it is called by ascending height.
+	Put(header *Header) error
+	// Get returns the header of height, nil if not exists.
+	Get(height int64) (*Header, error)
+	// Tip returns the highest header, nil if empty.
+	Tip() (*Header, error)
+}
+
+// MemoryHeaderStore HeaderStore in memory.
+type MemoryHeaderStore struct {
+	mu      sync.RWMutex
+	headers map[int64]*Header
+	tip     *Header
+}
+
+// NewMemoryHeaderStore new memory header store.
+func NewMemoryHeaderStore() *MemoryHeaderStore {
+	return &MemoryHeaderStore{headers: map[int64]*Header{}}
+}
+
+// Put implements HeaderStore.
+func (s *MemoryHeaderStore) Put(header *Header) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.headers[header.Height] = header
+	if s.tip == nil || header.Height > s.tip.Height {
+		s.tip = header
+	}
+	return nil
+}
+
+// Get implements HeaderStore.
+func (s *MemoryHeaderStore) Get(height int64) (*Header, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.headers[height], nil
+}
+
+// Tip implements HeaderStore.
+func (s *MemoryHeaderStore) Tip() (*Header, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tip, nil
+}
+
+// VerifyProposerSign verify the proposer address matches the public key in the block,
+// and the block id is signed by it. The genesis block is unsigned, it is trusted as a checkpoint.
+func VerifyProposerSign(block *pb.InternalBlock) error {
+	cryptoClient := crypto.GetCryptoClient()
+	publicKey, err := cryptoClient.GetEcdsaPublicKeyFromJsonStr(string(block.GetPubkey()))
+	if err != nil {
+		return errors.Wrapf(common.ErrInvalidSignature, "block %x public key: %v", block.GetBlockid(), err)
+	}
+	address, err := cryptoClient.GetAddressFromPublicKey(publicKey)
+	if err != nil || address != string(block.GetProposer()) {
+		return errors.Wrapf(common.ErrInvalidSignature, "block %x proposer %s not match public key", block.GetBlockid(), block.GetProposer())
+	}
+	if ok, err := cryptoClient.VerifyECDSA(publicKey, block.GetSign(), block.GetBlockid()); err != nil || !ok {
+		return errors.Wrapf(common.ErrInvalidSignature, "block %x", block.GetBlockid())
+	}
+	return nil
+}
+
+// VerifyHeader verify the block id, the proposer signature and the block links to prev.
+func VerifyHeader(block, prev *pb.InternalBlock) error {
+	return verifyHeader(block, NewHeader(prev))
+}
+
+func verifyHeader(block *pb.InternalBlock, prev *Header) error {
+	if block.GetHeight() != prev.Height+1 || !bytes.Equal(block.GetPreHash(), prev.Blockid) {
+		return errors.Wrapf(common.ErrHeaderNotLinked, "block %d %x, verified tip %d %x", block.GetHeight(), block.GetPreHash(), prev.Height, prev.Blockid)
+	}
+	if err := VerifyBlockID(block); err != nil {
+		return err
+	}
+	return VerifyProposerSign(block)
+}
+
+// BlockClient the methods of xuper.XClient used by LightClient.
+type BlockClient interface {
+	Client
+	QueryBlockByHeight(height int64, opts ...xuper.QueryOption) (*pb.Block, error)
+	QueryBlockChainStatus(opts ...xuper.QueryOption) (*pb.BCStatus, error)
+}
+
+// LightClient follow block headers from a trusted checkpoint and verify transactions against them,
+// so a single node need not be trusted.
+type LightClient struct {
+	client BlockClient
+	store  HeaderStore
+	opts   []xuper.QueryOption
+
+	mu sync.Mutex
+}
+
+// NewLightClient new light client.
+//
+// Parameters:
+//   - `client`    : xuper.XClient.
+//   - `store`     : Verified headers, such as NewMemoryHeaderStore.
+//   - `checkpoint`: Trusted block, such as the genesis block or one got out of band, it is saved if store is empty,
+//     otherwise it must be the same as the one in store.
+//   - `opts`      : Query options, such as xuper.WithQueryBcname.
+func NewLightClient(client BlockClient, store HeaderStore, checkpoint *pb.InternalBlock, opts ...xuper.QueryOption) (*LightClient, error) {
+	if checkpoint == nil {
+		return nil, errors.New("checkpoint can not be nil")
+	}
+	if err := VerifyBlockID(checkpoint); err != nil {
+		return nil, errors.Wrap(err, "invalid checkpoint")
+	}
+
+	saved, err := store.Get(checkpoint.GetHeight())
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case saved == nil:
+		if tip, err := store.Tip(); err != nil || tip != nil {
+			return nil, errors.Errorf("checkpoint %d not in non-empty header store", checkpoint.GetHeight())
+		}
+		if err := store.Put(NewHeader(checkpoint)); err != nil {
+			return nil, err
+		}
+	case !bytes.Equal(saved.Blockid, checkpoint.GetBlockid()):
+		return nil, errors.Errorf("checkpoint %x differs from stored header %x", checkpoint.GetBlockid(), saved.Blockid)
+	}
+	return &LightClient{client: client, store: store, opts: opts}, nil
+}
+
+// Tip returns the highest verified header.
+func (l *LightClient) Tip() (*Header, error) {
+	return l.store.Tip()
+}
+
+// SyncTo verify and save headers from the verified tip to height.
+func (l *LightClient) SyncTo(height int64) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	tip, err := l.store.Tip()
+	if err != nil {
+		return err
+	}
+	for h := tip.Height + 1; h <= height; h++ {
+		block, err := l.client.QueryBlockByHeight(h, l.opts...)
+		if err != nil {
+			return errors.Wrapf(err, "query block %d failed", h)
+		}
+		if err := verifyHeader(block.GetBlock(), tip); err != nil {
+			return err
+		}
+		tip = NewHeader(block.GetBlock())
+		if err := l.store.Put(tip); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Follow sync headers to the node tip every interval until ctx done or verification error,
+// blocks within confirmations of the tip are not synced because they may still be orphaned.
+func (l *LightClient) Follow(ctx context.Context, interval time.Duration, confirmations int64) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		status, err := l.client.QueryBlockChainStatus(l.opts...)
+		if err != nil {
+			return err
+		}
+		if err := l.SyncTo(status.GetBlock().GetHeight() - confirmations); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// VerifyTx query the transaction and its block, the block must match the verified header of its height
+// and include the transaction. Headers are synced to the block height if needed.
+func (l *LightClient) VerifyTx(txid string) (*pb.Transaction, error) {
+	tx, err := l.client.QueryTxByID(txid, l.opts...)
+	if err != nil {
+		return nil, err
+	}
+	if hex.EncodeToString(tx.GetTxid()) != txid {
+		return nil, errors.Wrapf(common.ErrTxIDMismatch, "query %s, got %x", txid, tx.GetTxid())
+	}
+	block, err := l.client.QueryBlockByID(hex.EncodeToString(tx.GetBlockid()), l.opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := l.SyncTo(block.GetBlock().GetHeight()); err != nil {
+		return nil, err
+	}
+	header, err := l.store.Get(block.GetBlock().GetHeight())
+	if err != nil {
+		return nil, err
+	}
+	if header == nil || !bytes.Equal(header.Blockid, block.GetBlock().GetBlockid()) {
+		return nil, errors.Wrapf(common.ErrHeaderNotLinked, "block %x of tx %s", block.GetBlock().GetBlockid(), txid)
+	}
+	if err := VerifyTxInBlock(tx, block.GetBlock()); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
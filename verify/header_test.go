@@ -0,0 +1,198 @@
+package verify
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/xuperchain/xuperchain/service/pb"
+
+	"github.com/superconsensus/matrix-sdk-go/v2/account"
+	"github.com/superconsensus/matrix-sdk-go/v2/common"
+	"github.com/superconsensus/matrix-sdk-go/v2/crypto"
+	"github.com/superconsensus/matrix-sdk-go/v2/xuper"
+)
+
+// newTestChain blocks of height 0 to n, block 0 is the unsigned genesis, others are signed by proposer.
+func newTestChain(t *testing.T, proposer *account.Account, n int) []*pb.InternalBlock {
+	cryptoClient := crypto.GetCryptoClient()
+	privateKey, err := cryptoClient.GetEcdsaPrivateKeyFromJsonStr(proposer.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	blocks := []*pb.InternalBlock{}
+	for h := 0; h <= n; h++ {
+		tx := &pb.Transaction{Version: 1, Desc: []byte{byte(h)}, Nonce: "nonce", Initiator: "alice"}
+		tx.Txid, err = common.MakeTransactionID(tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		block := &pb.InternalBlock{
+			Version:      1,
+			Height:       int64(h),
+			Timestamp:    int64(h),
+			TxCount:      1,
+			Transactions: []*pb.Transaction{tx},
+			MerkleRoot:   MerkleRoot([]*pb.Transaction{tx}),
+		}
+		if h > 0 {
+			block.PreHash = blocks[h-1].Blockid
+			block.Proposer = []byte(proposer.Address)
+			block.Pubkey = []byte(proposer.PublicKey)
+		}
+		block.Blockid, err = MakeBlockID(block)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if h > 0 {
+			block.Sign, err = cryptoClient.SignECDSA(privateKey, block.Blockid)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		tx.Blockid = block.Blockid
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+type mockBlockClient struct {
+	blocks []*pb.InternalBlock
+}
+
+func (m *mockBlockClient) QueryTxByID(txID string, opts ...xuper.QueryOption) (*pb.Transaction, error) {
+	for _, block := range m.blocks {
+		for _, tx := range block.Transactions {
+			if hex.EncodeToString(tx.Txid) == txID {
+				return tx, nil
+			}
+		}
+	}
+	return nil, common.ErrTxNotFound
+}
+
+func (m *mockBlockClient) QueryBlockByID(blockID string, opts ...xuper.QueryOption) (*pb.Block, error) {
+	for _, block := range m.blocks {
+		if hex.EncodeToString(block.Blockid) == blockID {
+			return &pb.Block{Blockid: block.Blockid, Block: block}, nil
+		}
+	}
+	return nil, errors.New("block not found")
+}
+
+func (m *mockBlockClient) QueryBlockByHeight(height int64, opts ...xuper.QueryOption) (*pb.Block, error) {
+	if height < 0 || height >= int64(len(m.blocks)) {
+		return nil, errors.New("block not found")
+	}
+	return &pb.Block{Blockid: m.blocks[height].Blockid, Block: m.blocks[height]}, nil
+}
+
+func (m *mockBlockClient) QueryBlockChainStatus(opts ...xuper.QueryOption) (*pb.BCStatus, error) {
+	return &pb.BCStatus{Block: m.blocks[len(m.blocks)-1]}, nil
+}
+
+func TestVerifyProposerSign(t *testing.T) {
+	proposer, err := account.CreateAccount(1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := account.CreateAccount(1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blocks := newTestChain(t, proposer, 2)
+	if err := VerifyHeader(blocks[2], blocks[1]); err != nil {
+		t.Error("VerifyHeader assert failed", err)
+	}
+	if err := VerifyHeader(blocks[2], blocks[0]); !errors.Is(err, common.ErrHeaderNotLinked) {
+		t.Error("VerifyHeader not linked assert failed", err)
+	}
+
+	cases := []struct {
+		name   string
+		tamper func(block *pb.InternalBlock)
+	}{
+		{"sign", func(block *pb.InternalBlock) { block.Sign = blocks[1].Sign }},
+		{"proposer", func(block *pb.InternalBlock) { block.Proposer = []byte(other.Address) }},
+		{"pubkey", func(block *pb.InternalBlock) { block.Pubkey = []byte(other.PublicKey) }},
+	}
+	for _, c := range cases {
+		block := *blocks[2]
+		c.tamper(&block)
+		if err := VerifyProposerSign(&block); !errors.Is(err, common.ErrInvalidSignature) {
+			t.Errorf("VerifyProposerSign tampered %s assert failed: %v", c.name, err)
+		}
+	}
+}
+
+func TestLightClient(t *testing.T) {
+	proposer, err := account.CreateAccount(1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &mockBlockClient{blocks: newTestChain(t, proposer, 5)}
+	store := NewMemoryHeaderStore()
+	lc, err := NewLightClient(client, store, client.blocks[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	txid := hex.EncodeToString(client.blocks[3].Transactions[0].Txid)
+	if tx, err := lc.VerifyTx(txid); err != nil || hex.EncodeToString(tx.Txid) != txid {
+		t.Fatal("VerifyTx assert failed", err)
+	}
+	if tip, _ := lc.Tip(); tip.Height != 3 {
+		t.Error("VerifyTx sync assert failed", tip.Height)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := lc.Follow(ctx, 10*time.Millisecond, 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Error("Follow assert failed", err)
+	}
+	if tip, _ := lc.Tip(); tip.Height != 4 {
+		t.Error("Follow confirmations assert failed", tip.Height)
+	}
+
+	// reopen with the same store and checkpoint.
+	if _, err := NewLightClient(client, store, client.blocks[0]); err != nil {
+		t.Error("NewLightClient reopen assert failed", err)
+	}
+	if _, err := NewLightClient(client, store, client.blocks[5]); err == nil {
+		t.Error("NewLightClient checkpoint not in store assert failed")
+	}
+
+	// a node serving a fork signed by another proposer from the same genesis.
+	mallory, err := account.CreateAccount(1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forked := newTestChain(t, mallory, 5)
+	forkClient := &mockBlockClient{blocks: forked}
+	lc, err = NewLightClient(forkClient, store, forked[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := lc.VerifyTx(hex.EncodeToString(forked[1].Transactions[0].Txid)); !errors.Is(err, common.ErrHeaderNotLinked) {
+		t.Error("VerifyTx forked block assert failed", err)
+	}
+	if err := lc.SyncTo(5); !errors.Is(err, common.ErrHeaderNotLinked) {
+		t.Error("SyncTo forked block assert failed", err)
+	}
+
+	// a node serving a block with a forged signature.
+	forked[1].Sign = client.blocks[1].Sign
+	lc, err = NewLightClient(forkClient, NewMemoryHeaderStore(), forked[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := lc.SyncTo(2); !errors.Is(err, common.ErrInvalidSignature) {
+		t.Error("SyncTo forged signature assert failed", err)
+	}
+	if tip, _ := lc.Tip(); tip.Height != 0 {
+		t.Error("SyncTo forged signature tip assert failed", tip.Height)
+	}
+}
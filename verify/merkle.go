@@ -0,0 +1,130 @@
+package verify
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/pkg/errors"
+	"github.com/xuperchain/xuperchain/service/pb"
+
+	"github.com/superconsensus/matrix-sdk-go/v2/common"
+)
+
+// MerkleTree build the merkle tree of txids the same as the chain ledger,
+// leaves are padded to a power of 2 with nil, the root is the last node.
+func MerkleTree(txs []*pb.Transaction) [][]byte {
+	txCount := len(txs)
+	if txCount == 0 {
+		return nil
+	}
+
+	leafSize := leafSize(txCount)
+	treeSize := leafSize*2 - 1
+	tree := make([][]byte, treeSize)
+	for i, tx := range txs {
+		tree[i] = tx.GetTxid()
+	}
+	parent := leafSize
+	for i := 0; i < treeSize-1; i += 2 {
+		switch {
+		case tree[i] == nil:
+			tree[parent] = nil
+		case tree[i+1] == nil:
+			// no right child, hash the left twice.
+			tree[parent] = hashPair(tree[i], tree[i])
+		default:
+			tree[parent] = hashPair(tree[i], tree[i+1])
+		}
+		parent++
+	}
+	return tree
+}
+
+// MerkleRoot returns the merkle root of txids, nil if txs is empty.
+func MerkleRoot(txs []*pb.Transaction) []byte {
+	tree := MerkleTree(txs)
+	if len(tree) == 0 {
+		return nil
+	}
+	return tree[len(tree)-1]
+}
+
+// VerifyMerkleRoot recompute the merkle root from the block transactions and compare with block.MerkleRoot.
+func VerifyMerkleRoot(block *pb.InternalBlock) error {
+	root := MerkleRoot(block.GetTransactions())
+	if root == nil || !bytes.Equal(root, block.GetMerkleRoot()) {
+		return errors.Wrapf(common.ErrMerkleRootMismatch, "block %x expect %x, got %x", block.GetBlockid(), block.GetMerkleRoot(), root)
+	}
+	return nil
+}
+
+// MerkleProof proves a txid is a leaf of the merkle tree, it can be verified by the merkle root only.
+type MerkleProof struct {
+	Txid []byte
+	// Index position of the tx in the block.
+	Index int
+	// Siblings from the leaf level to the level below the root, nil means the node is hashed with itself.
+	Siblings [][]byte
+}
+
+// NewMerkleProof build merkle proof of the txid from the block transactions.
+func NewMerkleProof(block *pb.InternalBlock, txid []byte) (*MerkleProof, error) {
+	txs := block.GetTransactions()
+	index := -1
+	for i, tx := range txs {
+		if bytes.Equal(tx.GetTxid(), txid) {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return nil, errors.Wrapf(common.ErrTxNotInBlock, "tx %x, block %x", txid, block.GetBlockid())
+	}
+
+	proof := &MerkleProof{Txid: txid, Index: index}
+	tree := MerkleTree(txs)
+	// offset of the current level in tree.
+	offset, width, i := 0, leafSize(len(txs)), index
+	for width > 1 {
+		proof.Siblings = append(proof.Siblings, tree[offset+(i^1)])
+		offset += width
+		width /= 2
+		i /= 2
+	}
+	return proof, nil
+}
+
+// Verify recompute the root from the proof and compare with root.
+func (p *MerkleProof) Verify(root []byte) error {
+	node, i := p.Txid, p.Index
+	for _, sibling := range p.Siblings {
+		switch {
+		case i%2 == 1:
+			node = hashPair(sibling, node)
+		case sibling == nil:
+			node = hashPair(node, node)
+		default:
+			node = hashPair(node, sibling)
+		}
+		i /= 2
+	}
+	if !bytes.Equal(node, root) {
+		return errors.Wrapf(common.ErrMerkleRootMismatch, "proof of tx %s", hex.EncodeToString(p.Txid))
+	}
+	return nil
+}
+
+func leafSize(txCount int) int {
+	size := 1
+	for size < txCount {
+		size <<= 1
+	}
+	return size
+}
+
+func hashPair(left, right []byte) []byte {
+	first := sha256.Sum256(append(append([]byte{}, left...), right...))
+	second := sha256.Sum256(first[:])
+	return second[:]
+}
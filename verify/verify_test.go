@@ -0,0 +1,156 @@
+package verify
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/xuperchain/xuperchain/service/pb"
+
+	"github.com/superconsensus/matrix-sdk-go/v2/common"
+	"github.com/superconsensus/matrix-sdk-go/v2/xuper"
+)
+
+func doubleSha256(data ...[]byte) []byte {
+	first := sha256.Sum256(bytes.Join(data, nil))
+	second := sha256.Sum256(first[:])
+	return second[:]
+}
+
+func newTestBlock(t *testing.T, txCount int) *pb.InternalBlock {
+	block := &pb.InternalBlock{
+		Version:     1,
+		Nonce:       2,
+		Proposer:    []byte("proposer"),
+		Pubkey:      []byte("pubkey"),
+		PreHash:     []byte{9, 9},
+		Height:      10,
+		Timestamp:   1234,
+		TxCount:     int32(txCount),
+		CurTerm:     3,
+		CurBlockNum: 4,
+		FailedTxs:   map[string]string{"bb": "err2", "aa": "err1"},
+		Justify:     &pb.QuorumCert{ProposalId: []byte{1}, ViewNumber: 9, SignInfos: &pb.QCSignInfos{QCSignInfos: []*pb.SignInfo{{Address: "a", PublicKey: "k", Sign: []byte{2}}}}},
+	}
+	for i := 0; i < txCount; i++ {
+		tx := &pb.Transaction{Version: 1, Desc: []byte{byte(i)}, Nonce: "nonce", Timestamp: int64(i), Initiator: "alice"}
+		txid, err := common.MakeTransactionID(tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tx.Txid = txid
+		block.Transactions = append(block.Transactions, tx)
+	}
+	block.MerkleRoot = MerkleRoot(block.Transactions)
+	id, err := MakeBlockID(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block.Blockid = id
+	for _, tx := range block.Transactions {
+		tx.Blockid = id
+	}
+	return block
+}
+
+func TestMerkleTree(t *testing.T) {
+	txs := []*pb.Transaction{{Txid: []byte{1}}, {Txid: []byte{2}}, {Txid: []byte{3}}}
+	left := doubleSha256([]byte{1}, []byte{2})
+	right := doubleSha256([]byte{3}, []byte{3})
+	if root := MerkleRoot(txs); !bytes.Equal(root, doubleSha256(left, right)) {
+		t.Errorf("MerkleRoot of 3 txs assert failed: %x", root)
+	}
+	if tree := MerkleTree(txs); len(tree) != 7 || tree[3] != nil {
+		t.Error("MerkleTree padding assert failed", len(tree))
+	}
+	if root := MerkleRoot(txs[:1]); !bytes.Equal(root, []byte{1}) {
+		t.Errorf("MerkleRoot of 1 tx assert failed: %x", root)
+	}
+	if MerkleRoot(nil) != nil {
+		t.Error("MerkleRoot of empty txs assert failed")
+	}
+
+	for _, count := range []int{1, 2, 3, 5, 8} {
+		block := newTestBlock(t, count)
+		for _, tx := range block.Transactions {
+			proof, err := NewMerkleProof(block, tx.Txid)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := proof.Verify(block.MerkleRoot); err != nil {
+				t.Errorf("MerkleProof of %d txs index %d assert failed: %v", count, proof.Index, err)
+			}
+			if err := proof.Verify([]byte("bad root")); !errors.Is(err, common.ErrMerkleRootMismatch) {
+				t.Error("MerkleProof bad root assert failed", err)
+			}
+		}
+	}
+	if _, err := NewMerkleProof(newTestBlock(t, 2), []byte{1}); !errors.Is(err, common.ErrTxNotInBlock) {
+		t.Error("NewMerkleProof not in block assert failed", err)
+	}
+}
+
+func TestVerifyBlock(t *testing.T) {
+	block := newTestBlock(t, 3)
+	if err := VerifyBlock(block); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyTxInBlock(block.Transactions[1], block); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		tamper func(b *pb.InternalBlock)
+		err    error
+	}{
+		{tamper: func(b *pb.InternalBlock) { b.Transactions[0].Desc = []byte("changed") }, err: common.ErrTxIDMismatch},
+		{tamper: func(b *pb.InternalBlock) { b.Transactions = b.Transactions[1:] }, err: common.ErrMerkleRootMismatch},
+		{tamper: func(b *pb.InternalBlock) { b.Timestamp++ }, err: common.ErrBlockIDMismatch},
+		{tamper: func(b *pb.InternalBlock) { b.FailedTxs["aa"] = "changed" }, err: common.ErrBlockIDMismatch},
+		{tamper: func(b *pb.InternalBlock) { b.Justify.SignInfos.QCSignInfos[0].Sign = []byte{3} }, err: common.ErrBlockIDMismatch},
+	}
+	for i, c := range cases {
+		block := newTestBlock(t, 3)
+		c.tamper(block)
+		if err := VerifyBlock(block); !errors.Is(err, c.err) {
+			t.Errorf("VerifyBlock case %d expect %v, got %v", i, c.err, err)
+		}
+	}
+
+	other := newTestBlock(t, 4).Transactions[3]
+	if err := VerifyTxInBlock(other, block); !errors.Is(err, common.ErrTxNotInBlock) {
+		t.Error("VerifyTxInBlock not in block assert failed", err)
+	}
+}
+
+type mockClient struct {
+	block *pb.InternalBlock
+}
+
+func (m *mockClient) QueryTxByID(txID string, opts ...xuper.QueryOption) (*pb.Transaction, error) {
+	for _, tx := range m.block.Transactions {
+		if hex.EncodeToString(tx.Txid) == txID {
+			return tx, nil
+		}
+	}
+	return nil, common.ErrTxNotFound
+}
+
+func (m *mockClient) QueryBlockByID(blockID string, opts ...xuper.QueryOption) (*pb.Block, error) {
+	return &pb.Block{Blockid: m.block.Blockid, Block: m.block}, nil
+}
+
+func TestVerifyTxOnChain(t *testing.T) {
+	client := &mockClient{block: newTestBlock(t, 3)}
+	txid := hex.EncodeToString(client.block.Transactions[2].Txid)
+	if _, block, err := VerifyTxOnChain(client, txid); err != nil || block.Height != 10 {
+		t.Error("VerifyTxOnChain assert failed", err)
+	}
+
+	client.block.Transactions[0].Initiator = "mallory"
+	if _, _, err := VerifyTxOnChain(client, txid); !errors.Is(err, common.ErrTxIDMismatch) {
+		t.Error("VerifyTxOnChain tampered block assert failed", err)
+	}
+}
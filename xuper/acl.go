@@ -1,9 +1,44 @@
 package xuper
 
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/xuperchain/xuperchain/service/pb"
+
+	"github.com/superconsensus/matrix-sdk-go/v2/account"
+	"github.com/superconsensus/matrix-sdk-go/v2/common"
+)
+
+// Permission rules of xuperchain, same as pb.PermissionRule.
+const (
+	// RuleNull no permission model.
+	RuleNull = int32(pb.PermissionRule_NULL)
+	// RuleSignThreshold the sum of signers weight in AksWeight must reach AcceptValue.
+	RuleSignThreshold = int32(pb.PermissionRule_SIGN_THRESHOLD)
+	// RuleSignAKSet all AKs of at least one set in AkSets must sign.
+	RuleSignAKSet = int32(pb.PermissionRule_SIGN_AKSET)
+	// RuleSignRate not supported by chain yet.
+	RuleSignRate = int32(pb.PermissionRule_SIGN_RATE)
+	// RuleSignSum not supported by chain yet.
+	RuleSignSum = int32(pb.PermissionRule_SIGN_SUM)
+	// RuleCAServer not supported by chain yet.
+	RuleCAServer = int32(pb.PermissionRule_CA_SERVER)
+	// RuleCommunityVote not supported by chain yet.
+	RuleCommunityVote = int32(pb.PermissionRule_COMMUNITY_VOTE)
+)
+
+// aclAKLimit max AKs count in ACL, same as chain.
+const aclAKLimit = 1024
+
 // ACL acl.
 type ACL struct {
 	PM        PermissionModel    `json:"pm"`
 	AksWeight map[string]float64 `json:"aksWeight"`
+	AkSets    *AKSets            `json:"akSets,omitempty"`
 }
 
 // PermissionModel acl permission model.
@@ -12,6 +47,29 @@ type PermissionModel struct {
 	AcceptValue float64 `json:"acceptValue"`
 }
 
+// AKSets AK sets of RuleSignAKSet ACL, the expression is not supported by chain yet.
+type AKSets struct {
+	Sets       map[string]*AKSet `json:"sets"`
+	Expression string            `json:"expression,omitempty"`
+}
+
+// AKSet AK set, all AKs must sign.
+type AKSet struct {
+	AKs []string `json:"aks"`
+}
+
+// ACLChange one difference between two ACLs.
+type ACLChange struct {
+	// Field changed field: rule, acceptValue, aksWeight or akSets.
+	Field string
+	// Key AK of aksWeight or set name of akSets, empty for other fields.
+	Key string
+	// Old value, empty if added.
+	Old string
+	// New value, empty if removed.
+	New string
+}
+
 // NewACL new ACl instance.
 func NewACL(rule int32, acceptValue float64) *ACL {
 	return &ACL{
@@ -31,10 +89,246 @@ func (a *ACL) AddAK(ak string, weight float64) {
 	a.AksWeight[ak] = weight
 }
 
+// AddAKSet add an AK set for RuleSignAKSet ACL, the set name is its sequence number.
+func (a *ACL) AddAKSet(aks ...string) {
+	if a.AkSets == nil {
+		a.AkSets = &AKSets{}
+	}
+	if a.AkSets.Sets == nil {
+		a.AkSets.Sets = make(map[string]*AKSet, 1)
+	}
+	a.AkSets.Sets[strconv.Itoa(len(a.AkSets.Sets)+1)] = &AKSet{AKs: aks}
+}
+
+// Validate check the ACL can be accepted by chain and can be satisfied,
+// only RuleSignThreshold and RuleSignAKSet are supported by chain now.
+func (a *ACL) Validate() error {
+	switch a.PM.Rule {
+	case RuleSignThreshold:
+		if len(a.AksWeight) == 0 || len(a.AksWeight) > aclAKLimit {
+			return errors.Wrapf(common.ErrInvalidACL, "AKs count must be in [1, %d]", aclAKLimit)
+		}
+		if a.PM.AcceptValue <= 0 {
+			return errors.Wrap(common.ErrInvalidACL, "accept value must be positive")
+		}
+
+		var sum float64
+		for ak, weight := range a.AksWeight {
+			if err := validateACLMember(ak); err != nil {
+				return err
+			}
+			if weight <= 0 {
+				return errors.Wrapf(common.ErrInvalidACL, "weight of %s must be positive", ak)
+			}
+			sum += weight
+		}
+		if sum < a.PM.AcceptValue {
+			return errors.Wrapf(common.ErrInvalidACL, "sum of weights %v can not reach accept value %v", sum, a.PM.AcceptValue)
+		}
+		return nil
+
+	case RuleSignAKSet:
+		if a.AkSets == nil || len(a.AkSets.Sets) == 0 || len(a.AkSets.Sets) > aclAKLimit {
+			return errors.Wrapf(common.ErrInvalidACL, "AK sets count must be in [1, %d]", aclAKLimit)
+		}
+		for name, set := range a.AkSets.Sets {
+			if set == nil || len(set.AKs) == 0 {
+				return errors.Wrapf(common.ErrInvalidACL, "AK set %s is empty", name)
+			}
+			for _, ak := range set.AKs {
+				if err := validateACLMember(ak); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+
+	case RuleNull, RuleSignRate, RuleSignSum, RuleCAServer, RuleCommunityVote:
+		return errors.Wrapf(common.ErrInvalidACL, "permission rule %d is not supported by chain", a.PM.Rule)
+	default:
+		return errors.Wrapf(common.ErrInvalidACL, "unknown permission rule %d", a.PM.Rule)
+	}
+}
+
+// IsSatisfiedBy returns true if signatures of the signers satisfy this ACL, it works offline.
+//
+// Parameters:
+//   - `signers`: AK addresses or contract accounts which have signed.
+func (a *ACL) IsSatisfiedBy(signers []string) (bool, error) {
+	signed := make(map[string]bool, len(signers))
+	for _, signer := range signers {
+		signed[signer] = true
+	}
+
+	switch a.PM.Rule {
+	case RuleSignThreshold:
+		var sum float64
+		for signer := range signed {
+			sum += a.AksWeight[signer]
+		}
+		return sum >= a.PM.AcceptValue, nil
+
+	case RuleSignAKSet:
+		if a.AkSets == nil {
+			return false, nil
+		}
+		for _, set := range a.AkSets.Sets {
+			if set == nil || len(set.AKs) == 0 {
+				continue
+			}
+			ok := true
+			for _, ak := range set.AKs {
+				if !signed[ak] {
+					ok = false
+					break
+				}
+			}
+			if ok {
+				return true, nil
+			}
+		}
+		return false, nil
+
+	default:
+		return false, errors.Wrapf(common.ErrInvalidACL, "permission rule %d is not supported", a.PM.Rule)
+	}
+}
+
+// Diff returns changes from this ACL to other, sorted by field and key, for audit logs.
+func (a *ACL) Diff(other *ACL) []ACLChange {
+	if other == nil {
+		other = &ACL{}
+	}
+
+	changes := []ACLChange{}
+	if a.PM.Rule != other.PM.Rule {
+		changes = append(changes, ACLChange{Field: "rule", Old: strconv.Itoa(int(a.PM.Rule)), New: strconv.Itoa(int(other.PM.Rule))})
+	}
+	if a.PM.AcceptValue != other.PM.AcceptValue {
+		changes = append(changes, ACLChange{Field: "acceptValue", Old: formatFloat(a.PM.AcceptValue), New: formatFloat(other.PM.AcceptValue)})
+	}
+
+	oldWeights, newWeights := map[string]string{}, map[string]string{}
+	for ak, weight := range a.AksWeight {
+		oldWeights[ak] = formatFloat(weight)
+	}
+	for ak, weight := range other.AksWeight {
+		newWeights[ak] = formatFloat(weight)
+	}
+	changes = append(changes, diffMap("aksWeight", oldWeights, newWeights)...)
+
+	changes = append(changes, diffMap("akSets", a.AkSets.toMap(), other.AkSets.toMap())...)
+	return changes
+}
+
+// String returns the change in format: field[key]: old -> new.
+func (c ACLChange) String() string {
+	field := c.Field
+	if c.Key != "" {
+		field = fmt.Sprintf("%s[%s]", c.Field, c.Key)
+	}
+
+	switch {
+	case c.Old == "":
+		return fmt.Sprintf("%s: added %s", field, c.New)
+	case c.New == "":
+		return fmt.Sprintf("%s: removed %s", field, c.Old)
+	default:
+		return fmt.Sprintf("%s: %s -> %s", field, c.Old, c.New)
+	}
+}
+
+func newAKSetsFromPB(pbSets *pb.AkSets) *AKSets {
+	if pbSets == nil {
+		return nil
+	}
+	sets := &AKSets{
+		Sets:       make(map[string]*AKSet, len(pbSets.GetSets())),
+		Expression: pbSets.GetExpression(),
+	}
+	for name, set := range pbSets.GetSets() {
+		sets.Sets[name] = &AKSet{AKs: set.GetAks()}
+	}
+	return sets
+}
+
+func (s *AKSets) toMap() map[string]string {
+	m := map[string]string{}
+	if s == nil {
+		return m
+	}
+	for name, set := range s.Sets {
+		if set == nil {
+			continue
+		}
+		aks := append([]string{}, set.AKs...)
+		sort.Strings(aks)
+		m[name] = strings.Join(aks, ",")
+	}
+	return m
+}
+
+func diffMap(field string, old, new map[string]string) []ACLChange {
+	keys := make([]string, 0, len(old)+len(new))
+	for k := range old {
+		keys = append(keys, k)
+	}
+	for k := range new {
+		if _, ok := old[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	changes := []ACLChange{}
+	for _, k := range keys {
+		if old[k] != new[k] {
+			changes = append(changes, ACLChange{Field: field, Key: k, Old: old[k], New: new[k]})
+		}
+	}
+	return changes
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// validateACLMember ACL member must be AK address or contract account.
+func validateACLMember(member string) error {
+	addr, err := account.ParseAddress(member)
+	if err != nil || !addr.ChecksumValid || addr.IsContractName() {
+		return errors.Wrapf(common.ErrInvalidACL, "invalid ACL member %s", member)
+	}
+	return nil
+}
+
+// NewMultisigACL new threshold ACL, every owner has weight 1 and threshold owners signatures are required.
+//
+// Parameters:
+//   - `owners`   : Co-owner AK addresses.
+//   - `threshold`: Number of owners signatures required, 1 <= threshold <= len(owners).
+func NewMultisigACL(owners []string, threshold int) (*ACL, error) {
+	if threshold < 1 || threshold > len(owners) {
+		return nil, fmt.Errorf("invalid multisig threshold %d of %d owners", threshold, len(owners))
+	}
+
+	acl := NewACL(RuleSignThreshold, float64(threshold))
+	for _, owner := range owners {
+		if addr, err := account.ParseAddress(owner); err != nil || !addr.IsAK() || !addr.ChecksumValid {
+			return nil, fmt.Errorf("invalid multisig owner %s", owner)
+		}
+		if _, ok := acl.AksWeight[owner]; ok {
+			return nil, fmt.Errorf("duplicate multisig owner %s", owner)
+		}
+		acl.AddAK(owner, 1)
+	}
+	return acl, nil
+}
+
 func getDefaultACL(address string) *ACL {
 	return &ACL{
 		PM: PermissionModel{
-			Rule:        1,
+			Rule:        RuleSignThreshold,
 			AcceptValue: 1.0,
 		},
 		AksWeight: map[string]float64{
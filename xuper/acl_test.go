@@ -1,6 +1,12 @@
 package xuper
 
-import "testing"
+import (
+	"errors"
+	"testing"
+
+	"github.com/superconsensus/matrix-sdk-go/v2/account"
+	"github.com/superconsensus/matrix-sdk-go/v2/common"
+)
 
 func TestAcl(t *testing.T) {
 	acl := NewACL(1, 1.0)
@@ -32,3 +38,134 @@ func TestAcl(t *testing.T) {
 		t.Error("Acl AddAK assert failed")
 	}
 }
+
+func TestACLValidate(t *testing.T) {
+	a, _ := account.CreateAccount(1, 1)
+	b, _ := account.CreateAccount(1, 1)
+
+	threshold := NewACL(RuleSignThreshold, 1.5)
+	threshold.AddAK(a.Address, 1)
+	threshold.AddAK(b.Address, 0.5)
+
+	akSets := NewACL(RuleSignAKSet, 0)
+	akSets.AddAKSet(a.Address, b.Address)
+	akSets.AddAKSet("XC1111111111111111@xuper")
+
+	unreachable := NewACL(RuleSignThreshold, 2)
+	unreachable.AddAK(a.Address, 1)
+
+	invalidAK := NewACL(RuleSignThreshold, 1)
+	invalidAK.AddAK("a", 1)
+
+	zeroWeight := NewACL(RuleSignThreshold, 1)
+	zeroWeight.AddAK(a.Address, 1)
+	zeroWeight.AddAK(b.Address, 0)
+
+	emptySet := NewACL(RuleSignAKSet, 0)
+	emptySet.AddAKSet()
+
+	cases := []struct {
+		acl   *ACL
+		valid bool
+		desc  string
+	}{
+		{acl: threshold, valid: true, desc: "threshold"},
+		{acl: akSets, valid: true, desc: "ak sets"},
+		{acl: getDefaultACL(a.Address), valid: true, desc: "default"},
+		{acl: unreachable, desc: "weights can not reach accept value"},
+		{acl: invalidAK, desc: "invalid ak"},
+		{acl: zeroWeight, desc: "zero weight"},
+		{acl: emptySet, desc: "empty ak set"},
+		{acl: NewACL(RuleSignAKSet, 0), desc: "no ak set"},
+		{acl: NewACL(RuleSignRate, 1), desc: "not supported rule"},
+		{acl: NewACL(100, 1), desc: "unknown rule"},
+	}
+	for _, c := range cases {
+		err := c.acl.Validate()
+		if c.valid && err != nil {
+			t.Errorf("ACL Validate %s err: %v", c.desc, err)
+		}
+		if !c.valid && !errors.Is(err, common.ErrInvalidACL) {
+			t.Errorf("ACL Validate %s expect invalid ACL, got %v", c.desc, err)
+		}
+	}
+
+	satisfyCases := []struct {
+		acl     *ACL
+		signers []string
+		expect  bool
+	}{
+		{acl: threshold, signers: []string{a.Address, b.Address}, expect: true},
+		{acl: threshold, signers: []string{a.Address, a.Address}},
+		{acl: threshold, signers: []string{b.Address}},
+		{acl: akSets, signers: []string{a.Address, b.Address}, expect: true},
+		{acl: akSets, signers: []string{"XC1111111111111111@xuper"}, expect: true},
+		{acl: akSets, signers: []string{a.Address}},
+	}
+	for i, c := range satisfyCases {
+		ok, err := c.acl.IsSatisfiedBy(c.signers)
+		if err != nil || ok != c.expect {
+			t.Errorf("ACL IsSatisfiedBy case %d assert failed: %v, %v", i, ok, err)
+		}
+	}
+	if _, err := NewACL(RuleSignRate, 1).IsSatisfiedBy(nil); err == nil {
+		t.Error("ACL IsSatisfiedBy not supported rule assert failed")
+	}
+
+	if _, err := NewSetMethodACLRequest(a, "counter", "increase", invalidAK); !errors.Is(err, common.ErrInvalidACL) {
+		t.Error("NewSetMethodACLRequest invalid ACL assert failed", err)
+	}
+	a.SetContractAccount("XC1111111111111111@xuper")
+	if _, err := NewSetAccountACLRequest(a, nil); !errors.Is(err, common.ErrInvalidACL) {
+		t.Error("NewSetAccountACLRequest nil ACL assert failed", err)
+	}
+}
+
+func TestACLDiff(t *testing.T) {
+	oldACL := NewACL(RuleSignThreshold, 1)
+	oldACL.AddAK("a", 1)
+	oldACL.AddAK("b", 0.5)
+
+	newACL := NewACL(RuleSignThreshold, 1.5)
+	newACL.AddAK("a", 1)
+	newACL.AddAK("b", 1)
+	newACL.AddAK("c", 0.5)
+
+	expect := []string{
+		"acceptValue: 1 -> 1.5",
+		"aksWeight[b]: 0.5 -> 1",
+		"aksWeight[c]: added 0.5",
+	}
+	changes := oldACL.Diff(newACL)
+	if len(changes) != len(expect) {
+		t.Fatalf("ACL Diff assert failed: %v", changes)
+	}
+	for i := range changes {
+		if changes[i].String() != expect[i] {
+			t.Errorf("ACL Diff assert failed, expect %s, got %s", expect[i], changes[i])
+		}
+	}
+
+	akSets := NewACL(RuleSignAKSet, 0)
+	akSets.AddAKSet("b", "a")
+	expect = []string{
+		"rule: 1 -> 2",
+		"acceptValue: 1 -> 0",
+		"aksWeight[a]: removed 1",
+		"aksWeight[b]: removed 0.5",
+		"akSets[1]: added a,b",
+	}
+	changes = oldACL.Diff(akSets)
+	if len(changes) != len(expect) {
+		t.Fatalf("ACL Diff assert failed: %v", changes)
+	}
+	for i := range changes {
+		if changes[i].String() != expect[i] {
+			t.Errorf("ACL Diff assert failed, expect %s, got %s", expect[i], changes[i])
+		}
+	}
+
+	if len(oldACL.Diff(oldACL)) != 0 {
+		t.Error("ACL Diff same ACL assert failed")
+	}
+}
@@ -0,0 +1,244 @@
+package xuper
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/xuperchain/xuperchain/service/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Directions of AddressTxRecord.
+const (
+	// TxDirectionIn the address received amount.
+	TxDirectionIn = "in"
+	// TxDirectionOut the address sent amount.
+	TxDirectionOut = "out"
+)
+
+const (
+	// feeAddress outputs to it are transaction fee.
+	feeAddress = "$"
+
+	defaultAddressTxsLimit = 20
+)
+
+// LatestHeight height of the tip for Blocks and Pagination.ToHeight, ToHeight may also be nil for it.
+const LatestHeight int64 = -1
+
+// AddressTxRecord one transfer of an address.
+type AddressTxRecord struct {
+	Txid      string
+	Direction string
+	// Counterpart receiver of TxDirectionOut, sender of TxDirectionIn, empty for coinbase.
+	Counterpart string
+	Amount      *big.Int
+	// Fee paid by the transaction, the same for all records of one transaction.
+	Fee       *big.Int
+	Height    int64
+	Blockid   string
+	Timestamp int64
+}
+
+// Pagination of QueryAddressTxs.
+type Pagination struct {
+	// FromHeight the lowest block height to scan.
+	FromHeight int64
+	// ToHeight the highest block height to scan, nil or LatestHeight means the tip.
+	ToHeight *int64
+	// Limit min records count of a page, a block is never split so a page may have more, default 20.
+	Limit int
+	// UnspentOnly only incoming transfers whose outputs are unspent, it uses the node's UTXO record RPC
+	// instead of scanning blocks, heights are ignored and the only page has at most Limit records.
+	// Blocks are scanned only if the node does not implement the RPC.
+	UnspentOnly bool
+}
+
+// AddressTxsPage a page of QueryAddressTxs.
+type AddressTxsPage struct {
+	// Records ordered by height from high to low.
+	Records []*AddressTxRecord
+	// HasMore there are blocks below this page to scan.
+	HasMore bool
+	// NextToHeight ToHeight of the next page, valid only if HasMore.
+	NextToHeight int64
+}
+
+// QueryAddressTxs query transfers of the address by scanning blocks from ToHeight down to FromHeight.
+// The UTXO record RPC of the node only knows unspent outputs, so it is used for UnspentOnly pages only,
+// the full history always scans blocks and costs one block query per height.
+//
+// Parameters:
+//   - `address`   : AK address or contract account.
+//   - `pagination`: Page range and size, nil means the latest page.
+func (x *XClient) QueryAddressTxs(address string, pagination *Pagination, opts ...QueryOption) (*AddressTxsPage, error) {
+	if address == "" {
+		return nil, errors.New("address can not be empty")
+	}
+	if pagination == nil {
+		pagination = &Pagination{}
+	}
+	limit := pagination.Limit
+	if limit <= 0 {
+		limit = defaultAddressTxsLimit
+	}
+
+	if pagination.UnspentOnly {
+		page, err := x.queryUnspentAddressTxs(address, limit, opts...)
+		if status.Code(errors.Cause(err)) != codes.Unimplemented {
+			return page, err
+		}
+		// node without UTXO record RPC, scan blocks and keep incoming records.
+		page, err = x.scanAddressTxs(address, pagination.FromHeight, pagination.ToHeight, limit, opts...)
+		if err != nil {
+			return nil, err
+		}
+		records := []*AddressTxRecord{}
+		for _, record := range page.Records {
+			if record.Direction == TxDirectionIn {
+				records = append(records, record)
+			}
+		}
+		page.Records = records
+		return page, nil
+	}
+	return x.scanAddressTxs(address, pagination.FromHeight, pagination.ToHeight, limit, opts...)
+}
+
+func (x *XClient) scanAddressTxs(address string, fromHeight int64, to *int64, limit int, opts ...QueryOption) (*AddressTxsPage, error) {
+	toHeight := LatestHeight
+	if to != nil {
+		toHeight = *to
+	}
+	if toHeight < 0 {
+		bcStatus, err := x.queryBlockChainStatus(opts...)
+		if err != nil {
+			return nil, err
+		}
+		toHeight = bcStatus.GetBlock().GetHeight()
+	}
+
+	page := &AddressTxsPage{Records: []*AddressTxRecord{}}
+	for height := toHeight; height >= fromHeight; height-- {
+		block, err := x.queryBlockByHeight(height, opts...)
+		if err != nil {
+			return nil, errors.Wrapf(err, "query block %d failed", height)
+		}
+		internalBlock := block.GetBlock()
+		for _, tx := range internalBlock.GetTransactions() {
+			page.Records = append(page.Records, newAddressTxRecords(address, tx, internalBlock)...)
+		}
+		if len(page.Records) >= limit {
+			if height > fromHeight {
+				page.HasMore = true
+				page.NextToHeight = height - 1
+			}
+			break
+		}
+	}
+	return page, nil
+}
+
+func (x *XClient) queryUnspentAddressTxs(address string, limit int, opts ...QueryOption) (*AddressTxsPage, error) {
+	opt, err := initQueryOpts(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	in := &pb.UtxoRecordDetail{
+		Bcname:       getBCname(opt),
+		AccountName:  address,
+		DisplayCount: int64(limit),
+	}
+	detail, err := x.xc.QueryUtxoRecord(context.TODO(), in)
+	if err != nil {
+		return nil, err
+	}
+	if detail == nil {
+		return nil, errors.New("empty UTXO record response")
+	}
+	if detail.GetHeader().GetError() != pb.XChainErrorEnum_SUCCESS {
+		return nil, errors.New(detail.GetHeader().GetError().String())
+	}
+
+	page := &AddressTxsPage{Records: []*AddressTxRecord{}}
+	seen := map[string]bool{}
+	for _, record := range []*pb.UtxoRecord{detail.GetOpenUtxoRecord(), detail.GetLockedUtxoRecord(), detail.GetFrozenUtxoRecord()} {
+		for _, item := range record.GetItem() {
+			if seen[item.GetRefTxid()] {
+				continue
+			}
+			seen[item.GetRefTxid()] = true
+
+			tx, err := x.queryTxByID(item.GetRefTxid(), opts...)
+			if err != nil {
+				return nil, err
+			}
+			block, err := x.queryBlockByID(hex.EncodeToString(tx.GetBlockid()), opts...)
+			if err != nil {
+				return nil, err
+			}
+			for _, r := range newAddressTxRecords(address, tx, block.GetBlock()) {
+				if r.Direction == TxDirectionIn {
+					page.Records = append(page.Records, r)
+				}
+			}
+		}
+	}
+	sort.SliceStable(page.Records, func(i, j int) bool {
+		return page.Records[i].Height > page.Records[j].Height
+	})
+	return page, nil
+}
+
+// newAddressTxRecords outputs to other addresses are outgoing if address is a sender,
+// outputs to address are incoming if address is not a sender, change outputs are skipped.
+func newAddressTxRecords(address string, pbtx *pb.Transaction, block *pb.InternalBlock) []*AddressTxRecord {
+	tx := DecodeTx(pbtx)
+
+	var sender string
+	senders := map[string]bool{}
+	for i, input := range tx.Inputs {
+		if i == 0 {
+			sender = input.FromAddr
+		}
+		senders[input.FromAddr] = true
+	}
+	fee := big.NewInt(0)
+	for _, output := range tx.Outputs {
+		if output.ToAddr == feeAddress {
+			fee.Add(fee, output.Amount)
+		}
+	}
+
+	records := []*AddressTxRecord{}
+	for _, output := range tx.Outputs {
+		if output.ToAddr == feeAddress || output.Amount.Sign() == 0 {
+			continue
+		}
+		record := &AddressTxRecord{
+			Txid:      tx.Txid,
+			Amount:    output.Amount,
+			Fee:       fee,
+			Height:    block.GetHeight(),
+			Blockid:   hex.EncodeToString(block.GetBlockid()),
+			Timestamp: tx.Timestamp,
+		}
+		switch {
+		case senders[address] && output.ToAddr != address:
+			record.Direction = TxDirectionOut
+			record.Counterpart = output.ToAddr
+		case !senders[address] && output.ToAddr == address:
+			record.Direction = TxDirectionIn
+			record.Counterpart = sender
+		default:
+			continue
+		}
+		records = append(records, record)
+	}
+	return records
+}
@@ -0,0 +1,181 @@
+package xuper
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/xuperchain/xuperchain/service/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// mockChainXClient mock node with blocks by height.
+type mockChainXClient struct {
+	*MockXClient
+	blocks     map[int64]*pb.InternalBlock
+	utxoRecord *pb.UtxoRecordDetail
+	utxoErr    error
+}
+
+func (m *mockChainXClient) GetBlockChainStatus(ctx context.Context, in *pb.BCStatus, opts ...grpc.CallOption) (*pb.BCStatus, error) {
+	var tip *pb.InternalBlock
+	for _, block := range m.blocks {
+		if tip == nil || block.GetHeight() > tip.GetHeight() {
+			tip = block
+		}
+	}
+	return &pb.BCStatus{Header: newHeader(), Bcname: in.GetBcname(), Block: tip}, nil
+}
+
+func (m *mockChainXClient) GetBlockByHeight(ctx context.Context, in *pb.BlockHeight, opts ...grpc.CallOption) (*pb.Block, error) {
+	block, ok := m.blocks[in.GetHeight()]
+	if !ok {
+		return &pb.Block{Header: newHeader()}, nil
+	}
+	return &pb.Block{Header: newHeader(), Blockid: block.GetBlockid(), Block: block}, nil
+}
+
+func (m *mockChainXClient) GetBlock(ctx context.Context, in *pb.BlockID, opts ...grpc.CallOption) (*pb.Block, error) {
+	for _, block := range m.blocks {
+		if string(block.GetBlockid()) == string(in.GetBlockid()) {
+			return &pb.Block{Header: newHeader(), Blockid: block.GetBlockid(), Block: block}, nil
+		}
+	}
+	return &pb.Block{Header: newHeader()}, nil
+}
+
+func (m *mockChainXClient) QueryTx(ctx context.Context, in *pb.TxStatus, opts ...grpc.CallOption) (*pb.TxStatus, error) {
+	for _, block := range m.blocks {
+		for _, tx := range block.GetTransactions() {
+			if string(tx.GetTxid()) == string(in.GetTxid()) {
+				return &pb.TxStatus{Header: newHeader(), Txid: in.GetTxid(), Tx: tx}, nil
+			}
+		}
+	}
+	return &pb.TxStatus{Header: newHeader()}, nil
+}
+
+func (m *mockChainXClient) QueryUtxoRecord(ctx context.Context, in *pb.UtxoRecordDetail, opts ...grpc.CallOption) (*pb.UtxoRecordDetail, error) {
+	if m.utxoErr != nil {
+		return nil, m.utxoErr
+	}
+	if m.utxoRecord == nil {
+		return nil, status.Error(codes.Unimplemented, "unknown method QueryUtxoRecord")
+	}
+	return m.utxoRecord, nil
+}
+
+func newMockChain(txs map[int64][]*pb.Transaction, tip int64) map[int64]*pb.InternalBlock {
+	blocks := map[int64]*pb.InternalBlock{}
+	for height := int64(0); height <= tip; height++ {
+		id := []byte{byte(height), 0xb}
+		for _, tx := range txs[height] {
+			tx.Blockid = id
+		}
+		blocks[height] = &pb.InternalBlock{Blockid: id, Height: height, Transactions: txs[height], Timestamp: height}
+	}
+	return blocks
+}
+
+func TestQueryAddressTxs(t *testing.T) {
+	feeTx := newMockTx("03", "alice", "bob", 30)
+	feeTx.TxOutputs = append(feeTx.TxOutputs, &pb.TxOutput{ToAddr: []byte("$"), Amount: big.NewInt(2).Bytes()})
+	mock := &mockChainXClient{
+		MockXClient: &MockXClient{},
+		blocks: newMockChain(map[int64][]*pb.Transaction{
+			1: {newMockTx("01", "alice", "bob", 10)},
+			3: {newMockTx("02", "carol", "alice", 20), feeTx},
+			5: {newMockTx("04", "bob", "alice", 40)},
+		}, 6),
+	}
+	xc := &XClient{xc: mock}
+
+	page, err := xc.QueryAddressTxs("alice", &Pagination{Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// block 3 is not split.
+	if len(page.Records) != 3 || !page.HasMore || page.NextToHeight != 2 {
+		t.Fatal("QueryAddressTxs first page assert failed", len(page.Records), page.NextToHeight)
+	}
+	in, out := page.Records[0], page.Records[2]
+	if in.Txid != "04" || in.Direction != TxDirectionIn || in.Counterpart != "bob" || in.Amount.Int64() != 40 || in.Height != 5 {
+		t.Error("QueryAddressTxs incoming record assert failed", in)
+	}
+	if out.Txid != "03" || out.Direction != TxDirectionOut || out.Counterpart != "bob" || out.Amount.Int64() != 30 || out.Fee.Int64() != 2 {
+		t.Error("QueryAddressTxs outgoing record assert failed", out)
+	}
+
+	page, err = xc.QueryAddressTxs("alice", &Pagination{ToHeight: &page.NextToHeight, Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Records) != 1 || page.Records[0].Txid != "01" || page.HasMore {
+		t.Error("QueryAddressTxs last page assert failed", page.Records, page.HasMore)
+	}
+
+	// node without UTXO record RPC falls back to scan.
+	page, err = xc.QueryAddressTxs("alice", &Pagination{UnspentOnly: true})
+	if err != nil || len(page.Records) != 2 {
+		t.Error("QueryAddressTxs unspent fallback assert failed", err)
+	}
+
+	// other errors of the UTXO record RPC are not hidden by the scan.
+	mock.utxoErr = errors.New("connection refused")
+	if _, err := xc.QueryAddressTxs("alice", &Pagination{UnspentOnly: true}); err == nil {
+		t.Error("QueryAddressTxs unspent error assert failed")
+	}
+	mock.utxoErr = nil
+
+	txid, _ := hex.DecodeString("02")
+	mock.utxoRecord = &pb.UtxoRecordDetail{
+		Header:         newHeader(),
+		OpenUtxoRecord: &pb.UtxoRecord{Item: []*pb.UtxoKey{{RefTxid: hex.EncodeToString(txid), Offset: "0", Amount: "20"}}},
+	}
+	page, err = xc.QueryAddressTxs("alice", &Pagination{UnspentOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Records) != 1 || page.Records[0].Txid != "02" || page.Records[0].Height != 3 || page.Records[0].Counterpart != "carol" {
+		t.Error("QueryAddressTxs unspent assert failed", page.Records)
+	}
+
+	if _, err := xc.QueryAddressTxs("", nil); err == nil {
+		t.Error("QueryAddressTxs empty address assert failed")
+	}
+}
+
+func TestQueryAddressTxsToGenesis(t *testing.T) {
+	mock := &mockChainXClient{
+		MockXClient: &MockXClient{},
+		blocks: newMockChain(map[int64][]*pb.Transaction{
+			0: {newMockTx("01", "miner", "alice", 10)},
+			1: {newMockTx("02", "bob", "alice", 20)},
+			2: {newMockTx("03", "bob", "alice", 30)},
+		}, 2),
+	}
+	xc := &XClient{xc: mock}
+
+	txids := []string{}
+	pagination := &Pagination{Limit: 1}
+	for i := 0; i < 5; i++ {
+		page, err := xc.QueryAddressTxs("alice", pagination)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, record := range page.Records {
+			txids = append(txids, record.Txid)
+		}
+		if !page.HasMore {
+			break
+		}
+		pagination.ToHeight = &page.NextToHeight
+	}
+	if len(txids) != 3 || txids[0] != "03" || txids[2] != "01" {
+		t.Error("QueryAddressTxs to genesis assert failed", txids)
+	}
+}
@@ -0,0 +1,236 @@
+package xuper
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/xuperchain/xuperchain/service/pb"
+
+	"github.com/superconsensus/matrix-sdk-go/v2/common"
+	"github.com/superconsensus/matrix-sdk-go/v2/crypto"
+)
+
+// Kinds of AuthRequireItem.
+const (
+	// AuthKindInitiator the initiator must sign in InitiatorSigns, or satisfy its ACL if it is a contract account.
+	AuthKindInitiator = "initiator"
+	// AuthKindAK AuthRequire is an AK address, it must sign.
+	AuthKindAK = "ak"
+	// AuthKindAccount AuthRequire is contract account/AK, the contract account ACL must be satisfied.
+	AuthKindAccount = "account"
+	// AuthKindMethod the contract method ACL of invoked contract must be satisfied.
+	AuthKindMethod = "method"
+)
+
+// AuthRequireItem one requirement of the transaction signatures.
+type AuthRequireItem struct {
+	// Kind AuthKindInitiator, AuthKindAK, AuthKindAccount or AuthKindMethod.
+	Kind string
+	// Name AK address, contract account or contract.method.
+	Name string
+	// ACL of contract account or contract method, nil for AK.
+	ACL *ACL
+	// Signers who have valid signatures and count for this requirement.
+	Signers []string
+	// Satisfied true if the requirement is satisfied.
+	Satisfied bool
+	// MissingWeight weight still missing of RuleSignThreshold ACL, 0 if satisfied or not threshold ACL.
+	MissingWeight float64
+}
+
+// AuthRequireReport result of CheckAuthRequire.
+type AuthRequireReport struct {
+	Items []*AuthRequireItem
+}
+
+// Satisfied returns true if all requirements are satisfied.
+func (r *AuthRequireReport) Satisfied() bool {
+	for _, item := range r.Items {
+		if !item.Satisfied {
+			return false
+		}
+	}
+	return true
+}
+
+// Err returns nil if all requirements are satisfied,
+// otherwise returns common.ErrAuthRequireNotSatisfied with the unsatisfied requirements.
+func (r *AuthRequireReport) Err() error {
+	missing := []string{}
+	for _, item := range r.Items {
+		if item.Satisfied {
+			continue
+		}
+		if item.MissingWeight > 0 {
+			missing = append(missing, fmt.Sprintf("%s %s missing weight %s", item.Kind, item.Name, formatFloat(item.MissingWeight)))
+		} else {
+			missing = append(missing, fmt.Sprintf("%s %s not signed", item.Kind, item.Name))
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return errors.Wrap(common.ErrAuthRequireNotSatisfied, strings.Join(missing, "; "))
+}
+
+// CheckAuthRequire evaluate InitiatorSigns against the initiator and AuthRequireSigns of the transaction
+// against AuthRequire, contract account ACLs and invoked contract method ACLs. PostTx calls it only if
+// the client is created WithAuthRequireCheck. Signatures are verified with the crypto of the client,
+// only valid ones are counted.
+func (x *XClient) CheckAuthRequire(tx *Transaction, opts ...QueryOption) (*AuthRequireReport, error) {
+	if tx == nil || tx.Tx == nil {
+		return nil, errors.New("transaction can not be nil")
+	}
+	if tx.Bcname != "" {
+		opts = append([]QueryOption{WithQueryBcname(tx.Bcname)}, opts...)
+	}
+
+	if err := tx.makeDigestHash(); err != nil {
+		return nil, err
+	}
+	signed := tx.signedAddresses(tx.Tx.GetAuthRequireSigns())
+
+	report := &AuthRequireReport{}
+	initiatorItem, err := x.checkInitiator(tx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	report.Items = append(report.Items, initiatorItem)
+
+	// identities which pass the check, used by method ACL.
+	identities := []string{}
+	accountAKs := map[string][]string{}
+	accounts := []string{}
+	for _, authRequire := range tx.Tx.GetAuthRequire() {
+		parts := strings.Split(authRequire, "/")
+		if len(parts) == 1 {
+			item := &AuthRequireItem{Kind: AuthKindAK, Name: authRequire, Satisfied: signed[authRequire]}
+			if item.Satisfied {
+				item.Signers = []string{authRequire}
+				identities = append(identities, authRequire)
+			}
+			report.Items = append(report.Items, item)
+			continue
+		}
+
+		contractAccount, ak := parts[0], parts[len(parts)-1]
+		if _, ok := accountAKs[contractAccount]; !ok {
+			accounts = append(accounts, contractAccount)
+			accountAKs[contractAccount] = []string{}
+		}
+		if signed[ak] && !inSlice(accountAKs[contractAccount], ak) {
+			accountAKs[contractAccount] = append(accountAKs[contractAccount], ak)
+		}
+	}
+
+	for _, contractAccount := range accounts {
+		acl, err := x.QueryAccountACL(contractAccount, opts...)
+		if err != nil {
+			return nil, errors.Wrapf(err, "query ACL of %s failed", contractAccount)
+		}
+		item, err := newACLAuthRequireItem(AuthKindAccount, contractAccount, acl, accountAKs[contractAccount])
+		if err != nil {
+			return nil, err
+		}
+		if item.Satisfied {
+			identities = append(identities, contractAccount)
+		}
+		report.Items = append(report.Items, item)
+	}
+
+	for _, req := range tx.Tx.GetContractRequests() {
+		if req.GetModuleName() == Xkernel3Module || req.GetModuleName() == XkernelModule || req.GetContractName() == "" {
+			continue
+		}
+		acl, err := x.QueryMethodACL(req.GetContractName(), req.GetMethodName(), opts...)
+		if err != nil {
+			return nil, errors.Wrapf(err, "query ACL of %s.%s failed", req.GetContractName(), req.GetMethodName())
+		}
+		// method without ACL can be invoked by anyone.
+		if acl == nil || (acl.PM.Rule == RuleNull && len(acl.AksWeight) == 0 && acl.AkSets == nil) {
+			continue
+		}
+		item, err := newACLAuthRequireItem(AuthKindMethod, req.GetContractName()+"."+req.GetMethodName(), acl, identities)
+		if err != nil {
+			return nil, err
+		}
+		report.Items = append(report.Items, item)
+	}
+
+	return report, nil
+}
+
+// checkInitiator an AK initiator must have a valid signature in InitiatorSigns,
+// a contract account initiator is checked against its ACL by the InitiatorSigns signers.
+func (x *XClient) checkInitiator(tx *Transaction, opts ...QueryOption) (*AuthRequireItem, error) {
+	initiator := tx.Tx.GetInitiator()
+	signed := tx.signedAddresses(tx.Tx.GetInitiatorSigns())
+	if !strings.Contains(initiator, "@") {
+		item := &AuthRequireItem{Kind: AuthKindInitiator, Name: initiator, Satisfied: signed[initiator]}
+		if item.Satisfied {
+			item.Signers = []string{initiator}
+		}
+		return item, nil
+	}
+
+	acl, err := x.QueryAccountACL(initiator, opts...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "query ACL of %s failed", initiator)
+	}
+	signers := []string{}
+	for address := range signed {
+		signers = append(signers, address)
+	}
+	sort.Strings(signers)
+	return newACLAuthRequireItem(AuthKindInitiator, initiator, acl, signers)
+}
+
+func newACLAuthRequireItem(kind, name string, acl *ACL, signers []string) (*AuthRequireItem, error) {
+	satisfied, err := acl.IsSatisfiedBy(signers)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s %s", kind, name)
+	}
+
+	item := &AuthRequireItem{
+		Kind:      kind,
+		Name:      name,
+		ACL:       acl,
+		Satisfied: satisfied,
+	}
+	for _, signer := range signers {
+		if _, ok := acl.AksWeight[signer]; ok || acl.PM.Rule == RuleSignAKSet {
+			item.Signers = append(item.Signers, signer)
+		}
+	}
+	if !satisfied && acl.PM.Rule == RuleSignThreshold {
+		var sum float64
+		for _, signer := range item.Signers {
+			sum += acl.AksWeight[signer]
+		}
+		item.MissingWeight = acl.PM.AcceptValue - sum
+	}
+	return item, nil
+}
+
+// signedAddresses returns addresses which have valid signature of DigestHash in sigs.
+func (t *Transaction) signedAddresses(sigs []*pb.SignatureInfo) map[string]bool {
+	cryptoClient := crypto.GetCryptoClient()
+	signed := make(map[string]bool, len(sigs))
+	for _, sig := range sigs {
+		publicKey, err := cryptoClient.GetEcdsaPublicKeyFromJsonStr(sig.GetPublicKey())
+		if err != nil {
+			continue
+		}
+		if ok, err := cryptoClient.VerifyECDSA(publicKey, sig.GetSign(), t.DigestHash); err != nil || !ok {
+			continue
+		}
+		address, err := cryptoClient.GetAddressFromPublicKey(publicKey)
+		if err != nil {
+			continue
+		}
+		signed[address] = true
+	}
+	return signed
+}
@@ -0,0 +1,150 @@
+package xuper
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/xuperchain/xuperchain/service/pb"
+	"google.golang.org/grpc"
+
+	"github.com/superconsensus/matrix-sdk-go/v2/account"
+	"github.com/superconsensus/matrix-sdk-go/v2/common"
+	"github.com/superconsensus/matrix-sdk-go/v2/common/config"
+)
+
+// mockAuthXClient mock node with account ACLs and method ACLs.
+type mockAuthXClient struct {
+	*MockXClient
+	acls map[string]*pb.Acl
+}
+
+func (m *mockAuthXClient) QueryACL(ctx context.Context, in *pb.AclStatus, opts ...grpc.CallOption) (*pb.AclStatus, error) {
+	name := in.GetAccountName()
+	if name == "" {
+		name = in.GetContractName() + "." + in.GetMethodName()
+	}
+	acl, ok := m.acls[name]
+	return &pb.AclStatus{Header: newHeader(), Confirmed: ok, Acl: acl}, nil
+}
+
+func TestCheckAuthRequire(t *testing.T) {
+	aks := make([]*account.Account, 3)
+	for i := range aks {
+		aks[i], _ = account.CreateAccount(1, 1)
+	}
+	contractAccount := "XC1111111111111111@xuper"
+
+	mock := &mockAuthXClient{
+		MockXClient: &MockXClient{},
+		acls: map[string]*pb.Acl{
+			contractAccount: {
+				Pm:        &pb.PermissionModel{Rule: pb.PermissionRule_SIGN_THRESHOLD, AcceptValue: 2},
+				AksWeight: map[string]float64{aks[0].Address: 1, aks[1].Address: 1, aks[2].Address: 1},
+			},
+			// xkernel methods are checked by the node itself.
+			"$acl.NewAccount": {
+				Pm:        &pb.PermissionModel{Rule: pb.PermissionRule_SIGN_THRESHOLD, AcceptValue: 1},
+				AksWeight: map[string]float64{aks[2].Address: 1},
+			},
+			"counter.increase": {
+				Pm:        &pb.PermissionModel{Rule: pb.PermissionRule_SIGN_THRESHOLD, AcceptValue: 1},
+				AksWeight: map[string]float64{contractAccount: 1},
+			},
+		},
+	}
+	xc := &XClient{
+		xc:  mock,
+		cfg: &config.CommConfig{ComplianceCheck: config.ComplianceCheckConfig{}},
+	}
+
+	tx := &Transaction{
+		Tx: &pb.Transaction{
+			Version:   1,
+			Desc:      []byte("auth check"),
+			Initiator: aks[0].Address,
+			AuthRequire: []string{
+				aks[0].Address,
+				contractAccount + "/" + aks[0].Address,
+				contractAccount + "/" + aks[1].Address,
+			},
+			ContractRequests: []*pb.InvokeRequest{
+				{ModuleName: "wasm", ContractName: "counter", MethodName: "increase"},
+				{ModuleName: Xkernel3Module, ContractName: "$acl", MethodName: "NewAccount"},
+				{ModuleName: XkernelModule, ContractName: "$acl", MethodName: "NewAccount"},
+			},
+		},
+	}
+	if err := tx.Sign(aks[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := xc.CheckAuthRequire(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Items) != 4 {
+		t.Fatalf("CheckAuthRequire items count expect 4, got %d", len(report.Items))
+	}
+	initiator, ak, acct, method := report.Items[0], report.Items[1], report.Items[2], report.Items[3]
+	if initiator.Kind != AuthKindInitiator || initiator.Name != aks[0].Address || !initiator.Satisfied {
+		t.Error("CheckAuthRequire initiator item assert failed", initiator)
+	}
+	if ak.Kind != AuthKindAK || !ak.Satisfied {
+		t.Error("CheckAuthRequire AK item assert failed", ak)
+	}
+	if acct.Kind != AuthKindAccount || acct.Name != contractAccount || acct.Satisfied || acct.MissingWeight != 1 {
+		t.Error("CheckAuthRequire account item assert failed", acct)
+	}
+	if method.Kind != AuthKindMethod || method.Name != "counter.increase" || method.Satisfied || method.MissingWeight != 1 {
+		t.Error("CheckAuthRequire method item assert failed", method)
+	}
+	if report.Satisfied() || !errors.Is(report.Err(), common.ErrAuthRequireNotSatisfied) {
+		t.Error("CheckAuthRequire report assert failed", report.Err())
+	}
+
+	// signature of account not in AuthRequire does not count.
+	tx.Tx.AuthRequire = append(tx.Tx.AuthRequire, contractAccount+"/"+aks[2].Address)
+	tx.Tx.AuthRequireSigns = append(tx.Tx.AuthRequireSigns, &pb.SignatureInfo{PublicKey: aks[2].PublicKey, Sign: []byte("bad sign")})
+	if report, err = xc.CheckAuthRequire(tx); err != nil || report.Satisfied() {
+		t.Error("CheckAuthRequire invalid signature assert failed", err)
+	}
+	tx.Tx.AuthRequire = tx.Tx.AuthRequire[:3]
+	tx.Tx.AuthRequireSigns = tx.Tx.AuthRequireSigns[:1]
+
+	// posting the under-signed tx fails only if the check is enabled.
+	checkClient := &XClient{xc: mock, opt: &clientOptions{authRequireCheck: true}}
+	if _, err := checkClient.PostTx(tx); !errors.Is(err, common.ErrAuthRequireNotSatisfied) {
+		t.Error("PostTx WithAuthRequireCheck under-signed assert failed", err)
+	}
+	if _, err := xc.PostTx(tx); err != nil {
+		t.Error("PostTx under-signed assert failed", err)
+	}
+
+	if err := tx.Sign(aks[1]); err != nil {
+		t.Fatal(err)
+	}
+	report, err = xc.CheckAuthRequire(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Satisfied() || report.Err() != nil {
+		t.Error("CheckAuthRequire satisfied assert failed", report.Err())
+	}
+	if len(report.Items[2].Signers) != 2 || report.Items[2].MissingWeight != 0 {
+		t.Error("CheckAuthRequire signers assert failed", report.Items[2].Signers)
+	}
+	if _, err := checkClient.PostTx(tx); err != nil {
+		t.Error("PostTx satisfied assert failed", err)
+	}
+
+	// initiator signature by another AK does not count.
+	tx.Tx.InitiatorSigns = tx.Tx.InitiatorSigns[1:]
+	if report, err = xc.CheckAuthRequire(tx); err != nil || report.Items[0].Satisfied || report.Satisfied() {
+		t.Error("CheckAuthRequire initiator signs assert failed", err)
+	}
+
+	if _, err := xc.CheckAuthRequire(nil); err == nil {
+		t.Error("CheckAuthRequire nil tx assert failed")
+	}
+}
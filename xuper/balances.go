@@ -0,0 +1,148 @@
+package xuper
+
+import (
+	"context"
+	"math/big"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/xuperchain/xuperchain/service/pb"
+
+	"github.com/superconsensus/matrix-sdk-go/v2/common"
+)
+
+const defaultQueryWorkers = 8
+
+// BalanceEntry balance of an address on a chain.
+type BalanceEntry struct {
+	Address string
+	Bcname  string
+	// Balance sum of Unfrozen and Frozen.
+	Balance  *big.Int
+	Unfrozen *big.Int
+	Frozen   *big.Int
+	// Err the entry query failed, the amounts are nil.
+	Err error
+}
+
+// BalanceMatrix balances of addresses on chains.
+type BalanceMatrix struct {
+	Addresses []string
+	Bcnames   []string
+	// Entries indexed by address then bcname, in the order of Addresses and Bcnames.
+	Entries [][]*BalanceEntry
+}
+
+// Get returns the entry of the address on the chain, nil if not queried.
+func (m *BalanceMatrix) Get(address, bcname string) *BalanceEntry {
+	for i, a := range m.Addresses {
+		if a != address {
+			continue
+		}
+		for j, b := range m.Bcnames {
+			if b == bcname {
+				return m.Entries[i][j]
+			}
+		}
+	}
+	return nil
+}
+
+// QueryBalances query balances of addresses on chains concurrently, one RPC per address covers all chains,
+// see WithQueryWorkers. An error of an entry is set to BalanceEntry.Err instead of failing the whole query.
+//
+// Parameters:
+//   - `addresses`: AK addresses or contract accounts.
+//   - `bcnames`  : Chain names, empty means the default chain xuper.
+func (x *XClient) QueryBalances(addresses []string, bcnames []string, opts ...QueryOption) (*BalanceMatrix, error) {
+	opt, err := initQueryOpts(opts...)
+	if err != nil {
+		return nil, err
+	}
+	workers := opt.workers
+	if workers == 0 {
+		workers = defaultQueryWorkers
+	}
+	if len(bcnames) == 0 {
+		bcnames = []string{getBCname(opt)}
+	}
+
+	m := &BalanceMatrix{
+		Addresses: addresses,
+		Bcnames:   bcnames,
+		Entries:   make([][]*BalanceEntry, len(addresses)),
+	}
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for i, address := range addresses {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int, address string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			m.Entries[i] = x.queryBalanceEntries(address, bcnames)
+		}(i, address)
+	}
+	wg.Wait()
+	return m, nil
+}
+
+func (x *XClient) queryBalanceEntries(address string, bcnames []string) []*BalanceEntry {
+	entries := make([]*BalanceEntry, len(bcnames))
+	tfds := make([]*pb.TokenFrozenDetails, 0, len(bcnames))
+	for i, bcname := range bcnames {
+		entries[i] = &BalanceEntry{Address: address, Bcname: bcname}
+		tfds = append(tfds, &pb.TokenFrozenDetails{Bcname: bcname})
+	}
+
+	bs, err := x.xc.GetBalanceDetail(context.TODO(), &pb.AddressBalanceStatus{Address: address, Tfds: tfds})
+	if err == nil && bs.GetHeader().GetError() != pb.XChainErrorEnum_SUCCESS {
+		err = errors.New(bs.GetHeader().GetError().String())
+	}
+	if err != nil {
+		for _, entry := range entries {
+			entry.Err = err
+		}
+		return entries
+	}
+
+	details := make(map[string]*pb.TokenFrozenDetails, len(bs.GetTfds()))
+	for _, tfd := range bs.GetTfds() {
+		details[tfd.GetBcname()] = tfd
+	}
+	for _, entry := range entries {
+		tfd, ok := details[entry.Bcname]
+		switch {
+		case !ok:
+			entry.Err = errors.New("invalid bcname:" + entry.Bcname)
+		case tfd.GetError() != pb.XChainErrorEnum_SUCCESS:
+			entry.Err = errors.New(tfd.GetError().String())
+		default:
+			entry.Err = entry.setAmounts(tfd.GetTfd())
+		}
+	}
+	return entries
+}
+
+func (e *BalanceEntry) setAmounts(details []*pb.TokenFrozenDetail) error {
+	unfrozen, frozen := big.NewInt(0), big.NewInt(0)
+	for _, detail := range details {
+		if detail.GetBalance() == "" {
+			continue
+		}
+		amount, ok := new(big.Int).SetString(detail.GetBalance(), 10)
+		if !ok {
+			return common.ErrInvalidAmount
+		}
+		if detail.GetIsFrozen() {
+			frozen.Add(frozen, amount)
+		} else {
+			unfrozen.Add(unfrozen, amount)
+		}
+	}
+	e.Unfrozen, e.Frozen = unfrozen, frozen
+	e.Balance = new(big.Int).Add(unfrozen, frozen)
+	return nil
+}
@@ -0,0 +1,97 @@
+package xuper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/xuperchain/xuperchain/service/pb"
+	"google.golang.org/grpc"
+)
+
+// mockBalanceXClient mock node, chain "hc" not exists, address "down" fails, records max concurrent RPCs.
+type mockBalanceXClient struct {
+	*MockXClient
+
+	mu          sync.Mutex
+	inflight    int
+	maxInflight int
+}
+
+func (m *mockBalanceXClient) GetBalanceDetail(ctx context.Context, in *pb.AddressBalanceStatus, opts ...grpc.CallOption) (*pb.AddressBalanceStatus, error) {
+	m.mu.Lock()
+	m.inflight++
+	if m.inflight > m.maxInflight {
+		m.maxInflight = m.inflight
+	}
+	m.mu.Unlock()
+	defer func() {
+		m.mu.Lock()
+		m.inflight--
+		m.mu.Unlock()
+	}()
+	time.Sleep(time.Millisecond)
+
+	if in.GetAddress() == "down" {
+		return nil, errors.New("connection refused")
+	}
+	out := &pb.AddressBalanceStatus{Header: newHeader(), Address: in.GetAddress()}
+	for _, tfd := range in.GetTfds() {
+		if tfd.GetBcname() == "hc" {
+			out.Tfds = append(out.Tfds, &pb.TokenFrozenDetails{Bcname: "hc", Error: pb.XChainErrorEnum_BLOCKCHAIN_NOTEXIST})
+			continue
+		}
+		out.Tfds = append(out.Tfds, &pb.TokenFrozenDetails{
+			Bcname: tfd.GetBcname(),
+			Tfd: []*pb.TokenFrozenDetail{
+				{Balance: "70"},
+				{Balance: "30", IsFrozen: true},
+			},
+		})
+	}
+	return out, nil
+}
+
+func TestQueryBalances(t *testing.T) {
+	mock := &mockBalanceXClient{MockXClient: &MockXClient{}}
+	xc := &XClient{xc: mock}
+
+	addresses := []string{"down"}
+	for i := 0; i < 20; i++ {
+		addresses = append(addresses, fmt.Sprintf("addr%d", i))
+	}
+	m, err := xc.QueryBalances(addresses, []string{"xuper", "hc"}, WithQueryWorkers(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mock.maxInflight > 3 {
+		t.Error("QueryBalances workers assert failed", mock.maxInflight)
+	}
+	if len(m.Entries) != len(addresses) || len(m.Entries[1]) != 2 {
+		t.Fatal("QueryBalances matrix size assert failed")
+	}
+
+	e := m.Get("addr7", "xuper")
+	if e == nil || e.Err != nil || e.Balance.Int64() != 100 || e.Unfrozen.Int64() != 70 || e.Frozen.Int64() != 30 {
+		t.Errorf("QueryBalances entry assert failed: %+v", e)
+	}
+	if e := m.Get("addr7", "hc"); e.Err == nil || e.Balance != nil {
+		t.Errorf("QueryBalances chain error assert failed: %+v", e)
+	}
+	for _, e := range m.Entries[0] {
+		if e.Err == nil {
+			t.Error("QueryBalances RPC error assert failed", e.Bcname)
+		}
+	}
+	if m.Get("nobody", "xuper") != nil {
+		t.Error("QueryBalances get not queried assert failed")
+	}
+
+	m, err = xc.QueryBalances([]string{"addr0"}, nil)
+	if err != nil || m.Get("addr0", "xuper").Balance.Int64() != 100 {
+		t.Error("QueryBalances default bcname assert failed", err)
+	}
+}
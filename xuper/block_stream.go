@@ -0,0 +1,159 @@
+package xuper
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/xuperchain/xuperchain/service/pb"
+)
+
+const defaultTxFetchWorkers = 8
+
+// FullBlock block with decoded transaction bodies.
+type FullBlock struct {
+	Bcname      string
+	Blockid     string
+	BlockHeight int64
+	Txs         []*DecodedTx
+}
+
+// FullBlockEvent block event of WatchFullBlocks.
+type FullBlockEvent struct {
+	Block *FullBlock
+	// Err stream or query error, it is the last event.
+	Err error
+}
+
+// FullBlockWatcher full blocks stream.
+type FullBlockWatcher struct {
+	// C closed after error, context done or Close called.
+	C <-chan *FullBlockEvent
+
+	cancel context.CancelFunc
+	once   sync.Once
+}
+
+// Close stop the watcher.
+func (w *FullBlockWatcher) Close() {
+	w.once.Do(w.cancel)
+}
+
+// WatchFullBlocks watch blocks like WatchBlockEvent, transaction bodies are fetched by QueryTxByID
+// concurrently with at most WithTxFetchWorkers workers, blocks and transactions keep their order.
+//
+// Parameters:
+//   - `ctx` : The watcher is stopped when ctx done.
+//   - `opts`: Block event options, such as WithBlockEventBcname, WithContract and WithTxFetchWorkers.
+func (x *XClient) WatchFullBlocks(ctx context.Context, opts ...BlockEventOption) (*FullBlockWatcher, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	watcher, err := x.watchBlockEvent(ctx, opts...)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	eventChan := make(chan *FullBlockEvent, watcher.opt.blockChanBufferSize)
+	fullWatcher := &FullBlockWatcher{C: eventChan, cancel: cancel}
+
+	send := func(event *FullBlockEvent) bool {
+		select {
+		case eventChan <- event:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	go func() {
+		defer func() {
+			close(eventChan)
+			fullWatcher.Close()
+			watcher.Close()
+			// unblock the watcher goroutine.
+			for range watcher.FilteredBlockChan {
+			}
+		}()
+
+		for block := range watcher.FilteredBlockChan {
+			fullBlock, err := x.FetchFullBlock(ctx, block, watcher.opt.txFetchWorkers)
+			if err != nil {
+				send(&FullBlockEvent{Err: err})
+				return
+			}
+			if !send(&FullBlockEvent{Block: fullBlock}) {
+				return
+			}
+		}
+		if ctx.Err() == nil && watcher.err != nil {
+			send(&FullBlockEvent{Err: watcher.err})
+		}
+	}()
+	return fullWatcher, nil
+}
+
+// FetchFullBlock fetch transaction bodies of the filtered block concurrently.
+//
+// Parameters:
+//   - `block`  : The filtered block, such as from WatchBlockEvent or WatchChainEvents.
+//   - `workers`: Max concurrent QueryTxByID, default 8 if <= 0.
+func (x *XClient) FetchFullBlock(ctx context.Context, block *FilteredBlock, workers int) (*FullBlock, error) {
+	if workers <= 0 {
+		workers = defaultTxFetchWorkers
+	}
+	txids := make([]string, 0, len(block.Txs))
+	for _, tx := range block.Txs {
+		txids = append(txids, tx.Txid)
+	}
+	txs, err := x.queryTxsByID(ctx, txids, workers, WithQueryBcname(block.Bcname))
+	if err != nil {
+		return nil, errors.Wrapf(err, "fetch transactions of block %d failed", block.BlockHeight)
+	}
+
+	fullBlock := &FullBlock{
+		Bcname:      block.Bcname,
+		Blockid:     block.Blockid,
+		BlockHeight: block.BlockHeight,
+		Txs:         make([]*DecodedTx, 0, len(txs)),
+	}
+	for i, tx := range txs {
+		dtx := DecodeTx(tx)
+		dtx.Events = block.Txs[i].Events
+		fullBlock.Txs = append(fullBlock.Txs, dtx)
+	}
+	return fullBlock, nil
+}
+
+// queryTxsByID query transactions concurrently with at most workers goroutines, results keep the order of txids.
+func (x *XClient) queryTxsByID(ctx context.Context, txids []string, workers int, opts ...QueryOption) ([]*pb.Transaction, error) {
+	txs := make([]*pb.Transaction, len(txids))
+	errs := make([]error, len(txids))
+
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for i, txid := range txids {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return nil, ctx.Err()
+		}
+
+		wg.Add(1)
+		go func(i int, txid string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			txs[i], errs[i] = x.queryTxByID(txid, opts...)
+		}(i, txid)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, errors.Wrapf(err, "query tx %s failed", txids[i])
+		}
+	}
+	return txs, nil
+}
@@ -0,0 +1,118 @@
+package xuper
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/xuperchain/xuperchain/service/pb"
+	"google.golang.org/grpc"
+)
+
+// mockTxXClient mock node with transactions, records max concurrent QueryTx.
+type mockTxXClient struct {
+	*MockXClient
+	txs map[string]*pb.Transaction
+
+	mu          sync.Mutex
+	inflight    int
+	maxInflight int
+}
+
+func (m *mockTxXClient) QueryTx(ctx context.Context, in *pb.TxStatus, opts ...grpc.CallOption) (*pb.TxStatus, error) {
+	m.mu.Lock()
+	m.inflight++
+	if m.inflight > m.maxInflight {
+		m.maxInflight = m.inflight
+	}
+	m.mu.Unlock()
+	defer func() {
+		m.mu.Lock()
+		m.inflight--
+		m.mu.Unlock()
+	}()
+	time.Sleep(time.Millisecond)
+
+	tx, ok := m.txs[hex.EncodeToString(in.GetTxid())]
+	if !ok {
+		return &pb.TxStatus{Header: newHeader()}, nil
+	}
+	return &pb.TxStatus{Header: newHeader(), Txid: in.GetTxid(), Tx: tx}, nil
+}
+
+func newMockTx(txid, from, to string, amount int64) *pb.Transaction {
+	id, _ := hex.DecodeString(txid)
+	return &pb.Transaction{
+		Txid:      id,
+		Initiator: from,
+		TxInputs: []*pb.TxInput{
+			{RefTxid: []byte{1}, FromAddr: []byte(from), Amount: big.NewInt(amount + 10).Bytes()},
+		},
+		TxOutputs: []*pb.TxOutput{
+			{ToAddr: []byte(to), Amount: big.NewInt(amount).Bytes()},
+			{ToAddr: []byte(from), Amount: big.NewInt(10).Bytes()},
+		},
+		ContractRequests: []*pb.InvokeRequest{
+			{ModuleName: "wasm", ContractName: "counter", MethodName: "increase", Amount: "5"},
+		},
+	}
+}
+
+func TestWatchFullBlocks(t *testing.T) {
+	mock := &mockTxXClient{MockXClient: &MockXClient{}, txs: map[string]*pb.Transaction{}}
+	block := &pb.FilteredBlock{Bcname: "xuper", Blockid: "b1", BlockHeight: 1}
+	txids := []string{"01", "02", "03", "04", "05", "06"}
+	for _, txid := range txids {
+		mock.txs[txid] = newMockTx(txid, "alice", "bob", 100)
+		block.Txs = append(block.Txs, &pb.FilteredTransaction{
+			Txid:   txid,
+			Events: []*pb.ContractEvent{{Contract: "counter", Name: "increase"}},
+		})
+	}
+	esc := &mockEventServiceClient{
+		blocks: []*pb.FilteredBlock{
+			block,
+			{Bcname: "xuper", Blockid: "b2", BlockHeight: 2, Txs: []*pb.FilteredTransaction{{Txid: "ff"}}},
+		},
+	}
+	xc := &XClient{xc: mock, esc: esc}
+
+	watcher, err := xc.WatchFullBlocks(context.Background(), WithTxFetchWorkers(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer watcher.Close()
+
+	event := <-watcher.C
+	if event == nil || event.Err != nil {
+		t.Fatal("WatchFullBlocks first block assert failed", event)
+	}
+	if len(event.Block.Txs) != len(txids) {
+		t.Fatalf("WatchFullBlocks expect %d txs, got %d", len(txids), len(event.Block.Txs))
+	}
+	for i, tx := range event.Block.Txs {
+		if tx.Txid != txids[i] || tx.Initiator != "alice" || len(tx.Events) != 1 {
+			t.Error("WatchFullBlocks tx order assert failed", i, tx.Txid)
+		}
+	}
+	tx := event.Block.Txs[0]
+	if tx.Inputs[0].Amount.Int64() != 110 || tx.Outputs[0].ToAddr != "bob" || tx.Outputs[0].Amount.Int64() != 100 ||
+		tx.ContractRequests[0].Amount.Int64() != 5 || tx.Inputs[0].RefTxid != "01" {
+		t.Error("WatchFullBlocks decode assert failed", tx)
+	}
+	if mock.maxInflight > 2 {
+		t.Error("WatchFullBlocks workers assert failed", mock.maxInflight)
+	}
+
+	// tx ff not found.
+	event = <-watcher.C
+	if event == nil || event.Err == nil {
+		t.Error("WatchFullBlocks query error assert failed", event)
+	}
+	if _, ok := <-watcher.C; ok {
+		t.Error("WatchFullBlocks closed assert failed")
+	}
+}
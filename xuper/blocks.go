@@ -0,0 +1,214 @@
+package xuper
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/xuperchain/xuperchain/service/pb"
+
+	"github.com/superconsensus/matrix-sdk-go/v2/common"
+)
+
+const defaultPollInterval = time.Second
+
+// BlockResult block of BlockIterator.
+type BlockResult struct {
+	Block *pb.Block
+	// Err query or reorganization error, it is the last result.
+	Err error
+}
+
+// BlockIterator trunk blocks by ascending height.
+type BlockIterator struct {
+	// C closed after the last block, error, context done or Close called.
+	C <-chan *BlockResult
+
+	cancel context.CancelFunc
+	once   sync.Once
+}
+
+// Close stop the iterator.
+func (it *BlockIterator) Close() {
+	it.once.Do(it.cancel)
+}
+
+type blockFetch struct {
+	block *pb.Block
+	err   error
+}
+
+// Blocks iterate trunk blocks from height from to to, blocks are prefetched concurrently with at most
+// WithQueryWorkers queries and delivered in order. Heights above the tip are waited for like WaitForHeight.
+// Every block must link to the one before it, a block got as branch is queried again after the poll interval,
+// ErrBlockReorganized is returned if a block delivered before is no longer in the trunk.
+//
+// Parameters:
+//   - `ctx` : The iterator is stopped when ctx done.
+//   - `from`: The first height.
+//   - `to`  : The last height, LatestHeight for the tip height when called.
+//   - `opts`: Query options, such as WithQueryBcname, WithQueryWorkers and WithQueryPollInterval.
+func (x *XClient) Blocks(ctx context.Context, from, to int64, opts ...QueryOption) (*BlockIterator, error) {
+	opt, err := initQueryOpts(opts...)
+	if err != nil {
+		return nil, err
+	}
+	if to == LatestHeight {
+		status, err := x.queryBlockChainStatus(opts...)
+		if err != nil {
+			return nil, err
+		}
+		to = status.GetBlock().GetHeight()
+	}
+	if from < 0 || from > to {
+		return nil, errors.Wrapf(common.ErrInvalidParam, "block range %d to %d", from, to)
+	}
+	workers := opt.workers
+	if workers == 0 {
+		workers = defaultQueryWorkers
+	}
+	interval := opt.pollInterval
+	if interval == 0 {
+		interval = defaultPollInterval
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	resultChan := make(chan *BlockResult, workers)
+	it := &BlockIterator{C: resultChan, cancel: cancel}
+
+	// fetches in height order, one more is held by the receiving loop, so at most workers in flight.
+	fetches := make(chan chan *blockFetch, workers-1)
+	go func() {
+		defer close(fetches)
+		var tip int64 = -1
+		for h := from; h <= to; h++ {
+			var waitErr error
+			if h > tip {
+				tip, waitErr = x.WaitForHeight(ctx, h, opts...)
+			}
+			fetch := make(chan *blockFetch, 1)
+			select {
+			case fetches <- fetch:
+			case <-ctx.Done():
+				return
+			}
+			if waitErr != nil {
+				fetch <- &blockFetch{err: waitErr}
+				return
+			}
+			go func(h int64) {
+				block, err := x.queryTrunkBlock(ctx, h, interval, opts...)
+				fetch <- &blockFetch{block: block, err: err}
+			}(h)
+		}
+	}()
+
+	go func() {
+		defer func() {
+			close(resultChan)
+			it.Close()
+		}()
+
+		send := func(result *BlockResult) bool {
+			select {
+			case resultChan <- result:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		var prev *pb.Block
+		h := from
+		for fetch := range fetches {
+			var f *blockFetch
+			select {
+			case f = <-fetch:
+			case <-ctx.Done():
+				return
+			}
+			if f.err == nil && prev != nil && !bytes.Equal(f.block.GetBlock().GetPreHash(), prev.GetBlockid()) {
+				// the trunk switched after prev was got.
+				f.block, f.err = x.queryTrunkBlock(ctx, h, interval, opts...)
+				if f.err == nil && !bytes.Equal(f.block.GetBlock().GetPreHash(), prev.GetBlockid()) {
+					f.err = errors.Wrapf(common.ErrBlockReorganized, "block %d %x", h-1, prev.GetBlockid())
+				}
+			}
+			if f.err != nil {
+				send(&BlockResult{Err: errors.Wrapf(f.err, "block %d", h)})
+				return
+			}
+			if !send(&BlockResult{Block: f.block}) {
+				return
+			}
+			prev = f.block
+			h++
+		}
+	}()
+	return it, nil
+}
+
+// queryTrunkBlock query the block of height, queried again once after interval if the node returns a branch block,
+// so the node has time to switch the trunk.
+func (x *XClient) queryTrunkBlock(ctx context.Context, height int64, interval time.Duration, opts ...QueryOption) (*pb.Block, error) {
+	for i := 0; i < 2; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(interval):
+			}
+		}
+		block, err := x.queryBlockByHeight(height, opts...)
+		if err != nil {
+			return nil, err
+		}
+		if block.GetStatus() != pb.Block_BRANCH {
+			return block, nil
+		}
+	}
+	return nil, errors.Wrapf(common.ErrBlockReorganized, "block %d not in trunk", height)
+}
+
+// LatestBlock query the tip block of the trunk.
+func (x *XClient) LatestBlock(opts ...QueryOption) (*pb.Block, error) {
+	status, err := x.queryBlockChainStatus(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return x.queryBlockByID(hex.EncodeToString(status.GetBlock().GetBlockid()), opts...)
+}
+
+// WaitForHeight poll the chain status every WithQueryPollInterval until the tip height reaches height,
+// returns the tip height.
+func (x *XClient) WaitForHeight(ctx context.Context, height int64, opts ...QueryOption) (int64, error) {
+	opt, err := initQueryOpts(opts...)
+	if err != nil {
+		return 0, err
+	}
+	interval := opt.pollInterval
+	if interval == 0 {
+		interval = defaultPollInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		status, err := x.queryBlockChainStatus(opts...)
+		if err != nil {
+			return 0, err
+		}
+		if tip := status.GetBlock().GetHeight(); tip >= height {
+			return tip, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package xuper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/xuperchain/xuperchain/service/pb"
+	"google.golang.org/grpc"
+
+	"github.com/superconsensus/matrix-sdk-go/v2/common"
+)
+
+// mockBlocksXClient mock chain whose tip grows by one every status query after it reaches grow.
+type mockBlocksXClient struct {
+	*MockXClient
+
+	mu          sync.Mutex
+	blocks      []*pb.InternalBlock
+	tip         int64
+	grow        bool
+	branchOnce  map[int64]bool
+	inflight    int
+	maxInflight int
+}
+
+func newMockBlocksXClient(n int, tip int64) *mockBlocksXClient {
+	m := &mockBlocksXClient{tip: tip, branchOnce: map[int64]bool{}}
+	var preHash []byte
+	for h := 0; h < n; h++ {
+		block := &pb.InternalBlock{Blockid: []byte(fmt.Sprintf("block%d", h)), PreHash: preHash, Height: int64(h)}
+		m.blocks = append(m.blocks, block)
+		preHash = block.Blockid
+	}
+	return m
+}
+
+func (m *mockBlocksXClient) GetBlockChainStatus(ctx context.Context, in *pb.BCStatus, opts ...grpc.CallOption) (*pb.BCStatus, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	tip := m.blocks[m.tip]
+	if m.grow && m.tip < int64(len(m.blocks))-1 {
+		m.tip++
+	}
+	return &pb.BCStatus{Header: newHeader(), Bcname: in.GetBcname(), Block: tip}, nil
+}
+
+func (m *mockBlocksXClient) GetBlockByHeight(ctx context.Context, in *pb.BlockHeight, opts ...grpc.CallOption) (*pb.Block, error) {
+	m.mu.Lock()
+	m.inflight++
+	if m.inflight > m.maxInflight {
+		m.maxInflight = m.inflight
+	}
+	status := pb.Block_TRUNK
+	if m.branchOnce[in.GetHeight()] {
+		m.branchOnce[in.GetHeight()] = false
+		status = pb.Block_BRANCH
+	}
+	tip := m.tip
+	m.mu.Unlock()
+	defer func() {
+		m.mu.Lock()
+		m.inflight--
+		m.mu.Unlock()
+	}()
+	time.Sleep(time.Millisecond)
+
+	if in.GetHeight() > tip {
+		return &pb.Block{Header: newHeader()}, nil
+	}
+	block := m.blocks[in.GetHeight()]
+	return &pb.Block{Header: newHeader(), Blockid: block.GetBlockid(), Status: status, Block: block}, nil
+}
+
+func (m *mockBlocksXClient) GetBlock(ctx context.Context, in *pb.BlockID, opts ...grpc.CallOption) (*pb.Block, error) {
+	for _, block := range m.blocks {
+		if string(block.GetBlockid()) == string(in.GetBlockid()) {
+			return &pb.Block{Header: newHeader(), Blockid: block.GetBlockid(), Status: pb.Block_TRUNK, Block: block}, nil
+		}
+	}
+	return &pb.Block{Header: newHeader()}, nil
+}
+
+func collectBlocks(it *BlockIterator) ([]int64, error) {
+	heights := []int64{}
+	for result := range it.C {
+		if result.Err != nil {
+			return heights, result.Err
+		}
+		heights = append(heights, result.Block.GetBlock().GetHeight())
+	}
+	return heights, nil
+}
+
+func TestBlocks(t *testing.T) {
+	mock := newMockBlocksXClient(30, 29)
+	mock.branchOnce[5] = true
+	xc := &XClient{xc: mock}
+
+	it, err := xc.Blocks(context.Background(), 2, LatestHeight, WithQueryWorkers(3), WithQueryPollInterval(time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	heights, err := collectBlocks(it)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(heights) != 28 {
+		t.Fatal("Blocks count assert failed", len(heights))
+	}
+	for i, h := range heights {
+		if h != int64(i+2) {
+			t.Fatal("Blocks order assert failed", heights)
+		}
+	}
+	if mock.maxInflight > 3 {
+		t.Error("Blocks workers assert failed", mock.maxInflight)
+	}
+
+	if _, err := xc.Blocks(context.Background(), 10, 5); !errors.Is(err, common.ErrInvalidParam) {
+		t.Error("Blocks invalid range assert failed", err)
+	}
+
+	// 0 is the genesis block, not the tip.
+	it, err = xc.Blocks(context.Background(), 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if heights, err := collectBlocks(it); err != nil || len(heights) != 1 || heights[0] != 0 {
+		t.Error("Blocks genesis assert failed", heights, err)
+	}
+}
+
+func TestBlocksWaitTip(t *testing.T) {
+	mock := newMockBlocksXClient(10, 3)
+	mock.grow = true
+	xc := &XClient{xc: mock}
+
+	it, err := xc.Blocks(context.Background(), 0, 9, WithQueryPollInterval(time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	heights, err := collectBlocks(it)
+	if err != nil || len(heights) != 10 {
+		t.Error("Blocks wait tip assert failed", heights, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	it, err = xc.Blocks(ctx, 5, 20, WithQueryPollInterval(time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	heights, _ = collectBlocks(it)
+	if len(heights) != 5 {
+		t.Error("Blocks context done assert failed", heights)
+	}
+}
+
+func TestBlocksReorganized(t *testing.T) {
+	mock := newMockBlocksXClient(10, 9)
+	mock.blocks[6] = &pb.InternalBlock{Blockid: []byte("fork6"), PreHash: []byte("fork5"), Height: 6}
+	xc := &XClient{xc: mock}
+
+	it, err := xc.Blocks(context.Background(), 0, 9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	heights, err := collectBlocks(it)
+	if !errors.Is(err, common.ErrBlockReorganized) || len(heights) != 6 {
+		t.Error("Blocks reorganized assert failed", heights, err)
+	}
+}
+
+func TestLatestBlockAndWaitForHeight(t *testing.T) {
+	mock := newMockBlocksXClient(10, 4)
+	xc := &XClient{xc: mock}
+
+	block, err := xc.LatestBlock()
+	if err != nil || block.GetBlock().GetHeight() != 4 {
+		t.Fatal("LatestBlock assert failed", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := xc.WaitForHeight(ctx, 8, WithQueryPollInterval(time.Millisecond)); err != context.DeadlineExceeded {
+		t.Error("WaitForHeight timeout assert failed", err)
+	}
+
+	mock.grow = true
+	tip, err := xc.WaitForHeight(context.Background(), 8, WithQueryPollInterval(time.Millisecond))
+	if err != nil || tip < 8 {
+		t.Error("WaitForHeight assert failed", tip, err)
+	}
+}
@@ -0,0 +1,149 @@
+package xuper
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/superconsensus/matrix-sdk-go/v2/account"
+	"github.com/superconsensus/matrix-sdk-go/v2/common"
+)
+
+const (
+	contractAccountPrefix = "XC"
+
+	// retry times to generate an unused contract account number.
+	generateContractAccountRetry = 10
+)
+
+var contractAccountNumberMax = big.NewInt(1e16)
+
+// GenerateContractAccount generate a random contract account which is not used on chain,
+// returns the full name such as XC1234567812345678@xuper.
+func (x *XClient) GenerateContractAccount(opts ...QueryOption) (string, error) {
+	opt, err := initQueryOpts(opts...)
+	if err != nil {
+		return "", err
+	}
+
+	for i := 0; i < generateContractAccountRetry; i++ {
+		n, err := rand.Int(rand.Reader, contractAccountNumberMax)
+		if err != nil {
+			return "", err
+		}
+		contractAccount := fmt.Sprintf("%s%016d@%s", contractAccountPrefix, n, getBCname(opt))
+
+		exists, err := x.ContractAccountExists(contractAccount, opts...)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return contractAccount, nil
+		}
+	}
+	return "", errors.New("generate unused contract account failed, please retry")
+}
+
+// ContractAccountExists returns true if the contract account is created on chain.
+//
+// Parameters:
+//   - `contractAccount`: such as XC1234567812345678@xuper.
+func (x *XClient) ContractAccountExists(contractAccount string, opts ...QueryOption) (bool, error) {
+	addr, err := account.ParseAddress(contractAccount)
+	if err != nil || !addr.IsContractAccount() {
+		return false, common.ErrInvalidContractAccount
+	}
+
+	opt, err := initQueryOpts(opts...)
+	if err != nil {
+		return false, err
+	}
+	if addr.Bcname == "" {
+		contractAccount = contractAccount + "@" + getBCname(opt)
+	}
+
+	acl, err := x.queryAccountPBACL(contractAccount, opts...)
+	if err != nil {
+		return false, err
+	}
+	return acl != nil, nil
+}
+
+// BindContractAccount find contract accounts which acc address belongs to by QueryAccountByAK,
+// and set the contract account to acc, returns the contract account.
+//
+// Parameters:
+//   - `acc`            : The account to bind.
+//   - `contractAccount`: The contract account to bind, must be one of the found ones, empty means the only one.
+func (x *XClient) BindContractAccount(acc *account.Account, contractAccount string, opts ...QueryOption) (string, error) {
+	if acc == nil {
+		return "", common.ErrInvalidAccount
+	}
+
+	contractAccounts, err := x.queryAccountByAK(acc.Address, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case len(contractAccounts) == 0:
+		return "", errors.Wrapf(common.ErrContractAccountNotFound, "address %s", acc.Address)
+	case contractAccount == "" && len(contractAccounts) > 1:
+		return "", fmt.Errorf("address %s belongs to multiple contract accounts: %s", acc.Address, strings.Join(contractAccounts, ","))
+	case contractAccount == "":
+		contractAccount = contractAccounts[0]
+	case !inSlice(contractAccounts, contractAccount):
+		return "", errors.Wrapf(common.ErrContractAccountNotFound, "address %s not in %s", acc.Address, contractAccount)
+	}
+
+	if err := acc.SetContractAccount(contractAccount); err != nil {
+		return "", err
+	}
+	return contractAccount, nil
+}
+
+// CreateContractAccountWithACL create contract account with the ACL instead of the initiator only default ACL.
+//
+// Parameters:
+//   - `from`           : Transaction initiator. NOTE: from must be NOT set contract account, if you set please remove it.
+//   - `contractAccount`: The contract account you want to create, such as: XC8888888899999999@xuper.
+//   - `acl`            : The ACL of the contract account, such as NewMultisigACL.
+func (x *XClient) CreateContractAccountWithACL(from *account.Account, contractAccount string, acl *ACL, opts ...RequestOption) (*Transaction, error) {
+	req, err := NewCreateContractAccountWithACLRequest(from, contractAccount, acl, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return x.Do(req)
+}
+
+// CreateMultisigContractAccount generate an unused contract account and create it with threshold multisig ACL,
+// returns the contract account and the transaction.
+//
+// Parameters:
+//   - `from`     : Transaction initiator, need not to be one of owners.
+//   - `owners`   : Co-owner AK addresses.
+//   - `threshold`: Number of owners signatures required.
+func (x *XClient) CreateMultisigContractAccount(from *account.Account, owners []string, threshold int, opts ...RequestOption) (string, *Transaction, error) {
+	acl, err := NewMultisigACL(owners, threshold)
+	if err != nil {
+		return "", nil, err
+	}
+
+	reqOpt, err := initOpts(opts...)
+	if err != nil {
+		return "", nil, err
+	}
+	contractAccount, err := x.GenerateContractAccount(WithQueryBcname(reqOpt.bcname))
+	if err != nil {
+		return "", nil, err
+	}
+
+	tx, err := x.CreateContractAccountWithACL(from, contractAccount, acl, opts...)
+	if err != nil {
+		return "", nil, err
+	}
+	return contractAccount, tx, nil
+}
@@ -0,0 +1,133 @@
+package xuper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/xuperchain/xuperchain/service/pb"
+	"google.golang.org/grpc"
+
+	"github.com/superconsensus/matrix-sdk-go/v2/account"
+	"github.com/superconsensus/matrix-sdk-go/v2/common"
+	"github.com/superconsensus/matrix-sdk-go/v2/common/config"
+)
+
+// mockACLXClient mock node with the given contract accounts.
+type mockACLXClient struct {
+	*MockXClient
+	accounts   map[string]bool
+	akAccounts []string
+}
+
+func (m *mockACLXClient) QueryACL(ctx context.Context, in *pb.AclStatus, opts ...grpc.CallOption) (*pb.AclStatus, error) {
+	if !m.accounts[in.GetAccountName()] {
+		return &pb.AclStatus{Header: newHeader()}, nil
+	}
+	return m.MockXClient.QueryACL(ctx, in, opts...)
+}
+
+func (m *mockACLXClient) GetAccountByAK(ctx context.Context, in *pb.AK2AccountRequest, opts ...grpc.CallOption) (*pb.AK2AccountResponse, error) {
+	return &pb.AK2AccountResponse{
+		Header:  newHeader(),
+		Bcname:  in.GetBcname(),
+		Account: m.akAccounts,
+	}, nil
+}
+
+func TestContractAccountHelpers(t *testing.T) {
+	mock := &mockACLXClient{
+		MockXClient: &MockXClient{},
+		accounts:    map[string]bool{"XC1111111111111111@xuper": true},
+	}
+	xc := &XClient{
+		xc:  mock,
+		cfg: &config.CommConfig{ComplianceCheck: config.ComplianceCheckConfig{}},
+	}
+
+	cases := []struct {
+		contractAccount string
+		exists          bool
+		hasErr          bool
+	}{
+		{contractAccount: "XC1111111111111111@xuper", exists: true},
+		{contractAccount: "XC1111111111111111", exists: true},
+		{contractAccount: "XC2222222222222222@xuper"},
+		{contractAccount: "XC2222@xuper", hasErr: true},
+	}
+	for _, c := range cases {
+		exists, err := xc.ContractAccountExists(c.contractAccount)
+		if c.hasErr != (err != nil) || exists != c.exists {
+			t.Errorf("ContractAccountExists %s assert failed: %v, %v", c.contractAccount, exists, err)
+		}
+	}
+	// QueryAccountACL of an account not created returns an empty ACL.
+	if acl, err := xc.QueryAccountACL("XC2222222222222222@xuper"); err != nil || acl == nil || len(acl.AksWeight) != 0 {
+		t.Error("QueryAccountACL not found assert failed", acl, err)
+	}
+
+	contractAccount, err := xc.GenerateContractAccount(WithQueryBcname("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr, err := account.ParseAddress(contractAccount); err != nil || !addr.IsContractAccount() || addr.Bcname != "hello" {
+		t.Error("GenerateContractAccount assert failed", contractAccount)
+	}
+
+	acc, _ := account.CreateAccount(1, 1)
+	if _, err := xc.BindContractAccount(acc, ""); !errors.Is(err, common.ErrContractAccountNotFound) {
+		t.Error("BindContractAccount not found assert failed", err)
+	}
+	mock.akAccounts = []string{"XC2222222222222222@xuper", "XC1111111111111111@xuper"}
+	if _, err := xc.BindContractAccount(acc, ""); err == nil {
+		t.Error("BindContractAccount multiple assert failed")
+	}
+	if _, err := xc.BindContractAccount(acc, "XC3333333333333333@xuper"); !errors.Is(err, common.ErrContractAccountNotFound) {
+		t.Error("BindContractAccount other assert failed", err)
+	}
+	mock.akAccounts = mock.akAccounts[:1]
+	if ca, err := xc.BindContractAccount(acc, ""); err != nil || ca != acc.GetContractAccount() || ca != "XC2222222222222222@xuper" {
+		t.Error("BindContractAccount assert failed", ca, err)
+	}
+}
+
+func TestCreateMultisigContractAccount(t *testing.T) {
+	xc := &XClient{
+		xc:  &mockACLXClient{MockXClient: &MockXClient{}},
+		cfg: &config.CommConfig{ComplianceCheck: config.ComplianceCheckConfig{}},
+	}
+
+	from, _ := account.CreateAccount(1, 1)
+	owner1, _ := account.CreateAccount(1, 1)
+	owner2, _ := account.CreateAccount(1, 1)
+	owners := []string{owner1.Address, owner2.Address}
+
+	if _, err := NewMultisigACL(owners, 3); err == nil {
+		t.Error("NewMultisigACL threshold assert failed")
+	}
+	if _, err := NewMultisigACL([]string{owner1.Address, owner1.Address}, 1); err == nil {
+		t.Error("NewMultisigACL duplicate owner assert failed")
+	}
+	if _, err := NewMultisigACL([]string{owner1.Address, "XC1111111111111111@xuper"}, 1); err == nil {
+		t.Error("NewMultisigACL invalid owner assert failed")
+	}
+
+	contractAccount, tx, err := xc.CreateMultisigContractAccount(from, owners, 2, WithNotPost())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args := tx.Tx.GetContractRequests()[0].GetArgs()
+	if !strings.Contains(contractAccount, string(args["account_name"])) || len(args["account_name"]) != 16 {
+		t.Error("CreateMultisigContractAccount account name assert failed", string(args["account_name"]))
+	}
+	acl := &ACL{}
+	if err := json.Unmarshal(args["acl"], acl); err != nil {
+		t.Fatal(err)
+	}
+	if acl.PM.AcceptValue != 2 || len(acl.AksWeight) != 2 || acl.AksWeight[owner1.Address] != 1 {
+		t.Error("CreateMultisigContractAccount ACL assert failed", acl)
+	}
+}
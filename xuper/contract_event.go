@@ -0,0 +1,282 @@
+package xuper
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/burrow/execution/evm/abi"
+	"github.com/pkg/errors"
+
+	"github.com/superconsensus/matrix-sdk-go/v2/common"
+)
+
+// EventDecoder decode contract event body to Go value.
+type EventDecoder interface {
+	Decode(event *ContractEvent) (interface{}, error)
+}
+
+// EventDecoderFunc function adapter of EventDecoder.
+type EventDecoderFunc func(event *ContractEvent) (interface{}, error)
+
+// Decode call f(event).
+func (f EventDecoderFunc) Decode(event *ContractEvent) (interface{}, error) {
+	return f(event)
+}
+
+// JSONEventDecoder decode JSON event body into registered types,
+// events not registered are decoded into map[string]interface{}.
+type JSONEventDecoder struct {
+	types map[string]reflect.Type
+}
+
+// NewJSONEventDecoder new JSON event decoder.
+func NewJSONEventDecoder() *JSONEventDecoder {
+	return &JSONEventDecoder{types: map[string]reflect.Type{}}
+}
+
+// Register the struct type of the event, such as Register("increase", IncreaseEvent{}),
+// the decoded value is a pointer such as *IncreaseEvent.
+func (d *JSONEventDecoder) Register(eventName string, prototype interface{}) *JSONEventDecoder {
+	d.types[eventName] = elemType(prototype)
+	return d
+}
+
+// Decode implements EventDecoder.
+func (d *JSONEventDecoder) Decode(event *ContractEvent) (interface{}, error) {
+	t, ok := d.types[event.Name]
+	if !ok {
+		v := map[string]interface{}{}
+		if err := unmarshalJSONUseNumber([]byte(event.Body), &v); err != nil {
+			return nil, errors.Wrapf(err, "decode event %s failed", event.Name)
+		}
+		return v, nil
+	}
+
+	v := reflect.New(t).Interface()
+	if err := json.Unmarshal([]byte(event.Body), v); err != nil {
+		return nil, errors.Wrapf(err, "decode event %s failed", event.Name)
+	}
+	return v, nil
+}
+
+// ProtoEventDecoder decode protobuf event body into registered messages.
+type ProtoEventDecoder struct {
+	types map[string]reflect.Type
+}
+
+// NewProtoEventDecoder new protobuf event decoder.
+func NewProtoEventDecoder() *ProtoEventDecoder {
+	return &ProtoEventDecoder{types: map[string]reflect.Type{}}
+}
+
+// Register the message type of the event, such as Register("increase", &pb.IncreaseEvent{}).
+func (d *ProtoEventDecoder) Register(eventName string, prototype proto.Message) *ProtoEventDecoder {
+	d.types[eventName] = elemType(prototype)
+	return d
+}
+
+// Decode implements EventDecoder.
+func (d *ProtoEventDecoder) Decode(event *ContractEvent) (interface{}, error) {
+	t, ok := d.types[event.Name]
+	if !ok {
+		return nil, errors.Wrapf(common.ErrUnknownEvent, "event %s", event.Name)
+	}
+
+	msg := reflect.New(t).Interface().(proto.Message)
+	if err := proto.Unmarshal([]byte(event.Body), msg); err != nil {
+		return nil, errors.Wrapf(err, "decode event %s failed", event.Name)
+	}
+	return msg, nil
+}
+
+// EVMEventDecoder decode EVM contract event by ABI, chain saves EVM event body as JSON array
+// of the event arguments, they are decoded into map[string]interface{} keyed by argument name.
+// Numbers are json.Number to keep uint256 precision.
+type EVMEventDecoder struct {
+	spec *abi.Spec
+}
+
+// NewEVMEventDecoder new EVM event decoder.
+//
+// Parameters:
+//   - `abiJSON`: The ABI of the EVM contract.
+func NewEVMEventDecoder(abiJSON []byte) (*EVMEventDecoder, error) {
+	spec, err := abi.ReadSpec(abiJSON)
+	if err != nil {
+		return nil, errors.Wrap(err, "read EVM ABI failed")
+	}
+	return &EVMEventDecoder{spec: spec}, nil
+}
+
+// Decode implements EventDecoder.
+func (d *EVMEventDecoder) Decode(event *ContractEvent) (interface{}, error) {
+	eventSpec, ok := d.spec.EventsByName[event.Name]
+	if !ok {
+		return nil, errors.Wrapf(common.ErrUnknownEvent, "event %s not in ABI", event.Name)
+	}
+
+	args := []interface{}{}
+	if err := unmarshalJSONUseNumber([]byte(event.Body), &args); err != nil {
+		return nil, errors.Wrapf(err, "decode event %s failed", event.Name)
+	}
+	if len(args) != len(eventSpec.Inputs) {
+		return nil, errors.Errorf("event %s expect %d arguments, got %d", event.Name, len(eventSpec.Inputs), len(args))
+	}
+
+	v := make(map[string]interface{}, len(args))
+	for i, input := range eventSpec.Inputs {
+		name := input.Name
+		if name == "" {
+			name = "arg" + strconv.Itoa(i)
+		}
+		v[name] = args[i]
+	}
+	return v, nil
+}
+
+// ContractEventMessage one contract event with the block and transaction it belongs to.
+type ContractEventMessage struct {
+	Bcname      string
+	Blockid     string
+	BlockHeight int64
+	Txid        string
+	Event       *ContractEvent
+
+	// Value decoded event body, nil if no decoder.
+	Value interface{}
+	// Err decode error, the raw event is still delivered.
+	Err error
+}
+
+// ContractEventSubscription contract events stream.
+type ContractEventSubscription struct {
+	// C closed when context done, Close called or the watcher stopped.
+	C <-chan *ContractEventMessage
+
+	watcher *Watcher
+	cancel  context.CancelFunc
+	once    sync.Once
+}
+
+// Close stop the subscription.
+func (s *ContractEventSubscription) Close() {
+	s.once.Do(func() {
+		s.cancel()
+		s.watcher.Close()
+	})
+}
+
+// SubscribeContractEvents subscribe events of the contract, built on WatchBlockEvent.
+// Use WithEventDecoder to decode event body into Go values.
+//
+// Parameters:
+//   - `ctx`       : The subscription is closed when ctx done.
+//   - `contract`  : The contract name.
+//   - `eventNames`: Event names to receive, empty means all events of the contract.
+//   - `opts`      : Block event options, such as WithBlockEventBcname and WithEventDecoder.
+func (x *XClient) SubscribeContractEvents(ctx context.Context, contract string, eventNames []string, opts ...BlockEventOption) (*ContractEventSubscription, error) {
+	if contract == "" {
+		return nil, errors.New("contract name can not be empty")
+	}
+
+	opts = append(opts, WithContract(exactMatchRegexp(contract)))
+	if len(eventNames) > 0 {
+		opts = append(opts, WithEventName(exactMatchRegexp(eventNames...)))
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	watcher, err := x.watchBlockEvent(ctx, opts...)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	names := make(map[string]bool, len(eventNames))
+	for _, name := range eventNames {
+		names[name] = true
+	}
+
+	msgChan := make(chan *ContractEventMessage, watcher.opt.blockChanBufferSize)
+	sub := &ContractEventSubscription{C: msgChan, watcher: watcher, cancel: cancel}
+	go func() {
+		defer func() {
+			close(msgChan)
+			sub.Close()
+			// unblock the watcher goroutine.
+			for range watcher.FilteredBlockChan {
+			}
+		}()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case block, ok := <-watcher.FilteredBlockChan:
+				if !ok {
+					return
+				}
+				for _, msg := range contractEventMessages(block, contract, names, watcher.opt.eventDecoder) {
+					select {
+					case msgChan <- msg:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}
+	}()
+	return sub, nil
+}
+
+func contractEventMessages(block *FilteredBlock, contract string, names map[string]bool, decoder EventDecoder) []*ContractEventMessage {
+	msgs := []*ContractEventMessage{}
+	for _, tx := range block.Txs {
+		for _, event := range tx.Events {
+			// node filters transactions by contract, other contracts' events in the same transaction are skipped.
+			if event.Contract != contract || (len(names) > 0 && !names[event.Name]) {
+				continue
+			}
+			msg := &ContractEventMessage{
+				Bcname:      block.Bcname,
+				Blockid:     block.Blockid,
+				BlockHeight: block.BlockHeight,
+				Txid:        tx.Txid,
+				Event:       event,
+			}
+			if decoder != nil {
+				msg.Value, msg.Err = decoder.Decode(event)
+			}
+			msgs = append(msgs, msg)
+		}
+	}
+	return msgs
+}
+
+// exactMatchRegexp node filters by regexp, returns regexp matches any of names exactly.
+func exactMatchRegexp(names ...string) string {
+	quoted := make([]string, 0, len(names))
+	for _, name := range names {
+		quoted = append(quoted, regexp.QuoteMeta(name))
+	}
+	return "^(" + strings.Join(quoted, "|") + ")$"
+}
+
+func elemType(prototype interface{}) reflect.Type {
+	t := reflect.TypeOf(prototype)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+func unmarshalJSONUseNumber(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
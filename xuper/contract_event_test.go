@@ -0,0 +1,221 @@
+package xuper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/xuperchain/xuperchain/service/pb"
+	"google.golang.org/grpc"
+
+	"github.com/superconsensus/matrix-sdk-go/v2/common"
+)
+
+// mockEventServiceClient mock event service, the stream sends blocks in filter range then EOF,
+// or blocks until context done if hold is true and range has no end.
+// If streams is set, each Subscribe uses the next one instead of blocks.
+type mockEventServiceClient struct {
+	blocks  []*pb.FilteredBlock
+	streams [][]*pb.FilteredBlock
+	hold    bool
+
+	mu      sync.Mutex
+	filters []*pb.BlockFilter
+}
+
+func (m *mockEventServiceClient) Subscribe(ctx context.Context, in *pb.SubscribeRequest, opts ...grpc.CallOption) (pb.EventService_SubscribeClient, error) {
+	filter := &pb.BlockFilter{}
+	if err := proto.Unmarshal(in.GetFilter(), filter); err != nil {
+		return nil, err
+	}
+	m.mu.Lock()
+	m.filters = append(m.filters, filter)
+	all := m.blocks
+	if len(m.streams) > 0 {
+		all, m.streams = m.streams[0], m.streams[1:]
+	}
+	m.mu.Unlock()
+
+	// blocks in [start, end) like node, empty end means following.
+	start, end := int64(math.MinInt64), int64(math.MaxInt64)
+	if filter.GetRange().GetStart() != "" {
+		start, _ = strconv.ParseInt(filter.GetRange().GetStart(), 10, 64)
+	}
+	if filter.GetRange().GetEnd() != "" {
+		end, _ = strconv.ParseInt(filter.GetRange().GetEnd(), 10, 64)
+	}
+	blocks := []*pb.FilteredBlock{}
+	for _, block := range all {
+		if block.GetBlockHeight() >= start && block.GetBlockHeight() < end {
+			blocks = append(blocks, block)
+		}
+	}
+	return &mockEventStream{ctx: ctx, blocks: blocks, hold: m.hold && end == math.MaxInt64}, nil
+}
+
+func (m *mockEventServiceClient) lastFilter() *pb.BlockFilter {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.filters[len(m.filters)-1]
+}
+
+type mockEventStream struct {
+	grpc.ClientStream
+	ctx    context.Context
+	blocks []*pb.FilteredBlock
+	hold   bool
+}
+
+func (s *mockEventStream) Recv() (*pb.Event, error) {
+	if len(s.blocks) == 0 {
+		if !s.hold {
+			return nil, io.EOF
+		}
+		<-s.ctx.Done()
+		return nil, s.ctx.Err()
+	}
+	block := s.blocks[0]
+	s.blocks = s.blocks[1:]
+	payload, err := proto.Marshal(block)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.Event{Payload: payload}, nil
+}
+
+func (s *mockEventStream) CloseSend() error {
+	return nil
+}
+
+type increaseEvent struct {
+	Key   string `json:"key"`
+	Value int64  `json:"value"`
+}
+
+func TestSubscribeContractEvents(t *testing.T) {
+	esc := &mockEventServiceClient{
+		blocks: []*pb.FilteredBlock{
+			{
+				Bcname:      "xuper",
+				Blockid:     "b1",
+				BlockHeight: 1,
+				Txs: []*pb.FilteredTransaction{
+					{
+						Txid: "t1",
+						Events: []*pb.ContractEvent{
+							{Contract: "counter", Name: "increase", Body: []byte(`{"key":"a","value":1}`)},
+							{Contract: "other", Name: "increase", Body: []byte(`{}`)},
+							{Contract: "counter", Name: "ignored", Body: []byte(`{}`)},
+						},
+					},
+				},
+			},
+			{Bcname: "xuper", Blockid: "b2", BlockHeight: 2},
+			{
+				Bcname:      "xuper",
+				Blockid:     "b3",
+				BlockHeight: 3,
+				Txs: []*pb.FilteredTransaction{
+					{
+						Txid:   "t3",
+						Events: []*pb.ContractEvent{{Contract: "counter", Name: "reset", Body: []byte(`bad json`)}},
+					},
+				},
+			},
+		},
+	}
+	xc := &XClient{esc: esc}
+
+	decoder := NewJSONEventDecoder().Register("increase", increaseEvent{})
+	sub, err := xc.SubscribeContractEvents(context.Background(), "counter", []string{"increase", "reset"}, WithEventDecoder(decoder))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+
+	msgs := []*ContractEventMessage{}
+	for msg := range sub.C {
+		msgs = append(msgs, msg)
+	}
+
+	filter := esc.lastFilter()
+	if filter.GetContract() != "^(counter)$" || filter.GetEventName() != "^(increase|reset)$" {
+		t.Error("SubscribeContractEvents filter assert failed", filter)
+	}
+	if len(msgs) != 2 {
+		t.Fatalf("SubscribeContractEvents expect 2 events, got %d", len(msgs))
+	}
+	if v, ok := msgs[0].Value.(*increaseEvent); !ok || msgs[0].Err != nil || v.Key != "a" || v.Value != 1 ||
+		msgs[0].BlockHeight != 1 || msgs[0].Txid != "t1" {
+		t.Error("SubscribeContractEvents decode assert failed", msgs[0])
+	}
+	if msgs[1].Err == nil || msgs[1].Event.Name != "reset" || msgs[1].BlockHeight != 3 {
+		t.Error("SubscribeContractEvents decode error assert failed", msgs[1])
+	}
+
+	if _, err := xc.SubscribeContractEvents(context.Background(), "", nil); err == nil {
+		t.Error("SubscribeContractEvents empty contract assert failed")
+	}
+}
+
+func TestSubscribeContractEventsCancel(t *testing.T) {
+	xc := &XClient{esc: &mockEventServiceClient{hold: true}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sub, err := xc.SubscribeContractEvents(ctx, "counter", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	if _, ok := <-sub.C; ok {
+		t.Error("SubscribeContractEvents cancel assert failed")
+	}
+	sub.Close()
+}
+
+func TestEventDecoders(t *testing.T) {
+	body, _ := proto.Marshal(&pb.ContractEvent{Contract: "counter", Name: "inner"})
+	protoDecoder := NewProtoEventDecoder().Register("increase", &pb.ContractEvent{})
+	v, err := protoDecoder.Decode(&ContractEvent{Name: "increase", Body: string(body)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e, ok := v.(*pb.ContractEvent); !ok || e.GetName() != "inner" {
+		t.Error("ProtoEventDecoder assert failed", v)
+	}
+	if _, err := protoDecoder.Decode(&ContractEvent{Name: "reset"}); !errors.Is(err, common.ErrUnknownEvent) {
+		t.Error("ProtoEventDecoder unknown event assert failed", err)
+	}
+
+	abiJSON := `[{"anonymous":false,"inputs":[{"indexed":true,"name":"from","type":"address"},{"indexed":false,"name":"value","type":"uint256"}],"name":"Transfer","type":"event"}]`
+	evmDecoder, err := NewEVMEventDecoder([]byte(abiJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err = evmDecoder.Decode(&ContractEvent{Name: "Transfer", Body: `["313131312D2D2D2D2D2D2D2D2D2D2D2D2D2D2D2D",115792089237316195423570985008687907853269984665640564039457584007913129639935]`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := v.(map[string]interface{})
+	if args["value"] != json.Number("115792089237316195423570985008687907853269984665640564039457584007913129639935") ||
+		args["from"] != "313131312D2D2D2D2D2D2D2D2D2D2D2D2D2D2D2D" {
+		t.Error("EVMEventDecoder assert failed", args)
+	}
+	if _, err := evmDecoder.Decode(&ContractEvent{Name: "Transfer", Body: `[1]`}); err == nil {
+		t.Error("EVMEventDecoder arguments count assert failed")
+	}
+	if _, err := evmDecoder.Decode(&ContractEvent{Name: "Approval", Body: `[]`}); !errors.Is(err, common.ErrUnknownEvent) {
+		t.Error("EVMEventDecoder unknown event assert failed", err)
+	}
+
+	v, err = NewJSONEventDecoder().Decode(&ContractEvent{Name: "any", Body: `{"n":1}`})
+	if err != nil || v.(map[string]interface{})["n"] != json.Number("1") {
+		t.Error("JSONEventDecoder unregistered event assert failed", v, err)
+	}
+}
@@ -0,0 +1,259 @@
+package xuper
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/xuperchain/xuperchain/service/pb"
+
+	"github.com/superconsensus/matrix-sdk-go/v2/crypto"
+)
+
+// TxKind classification of a transaction by its contract requests.
+type TxKind string
+
+// Kinds of DecodedTx.
+const (
+	// TxKindTransfer no contract request, including coinbase.
+	TxKindTransfer TxKind = "transfer"
+	// TxKindDeploy deploy or upgrade a contract.
+	TxKindDeploy TxKind = "deploy"
+	// TxKindInvoke invoke a contract.
+	TxKindInvoke TxKind = "invoke"
+	// TxKindACLChange set contract account ACL or contract method ACL.
+	TxKindACLChange TxKind = "acl_change"
+	// TxKindAccountCreation create a contract account.
+	TxKindAccountCreation TxKind = "account_creation"
+)
+
+// Roles of DecodedSigner.
+const (
+	// SignerRoleInitiator signer of the initiator.
+	SignerRoleInitiator = "initiator"
+	// SignerRoleAuthRequire signer of auth require.
+	SignerRoleAuthRequire = "auth_require"
+)
+
+// DecodedTx transaction with hex ids, string addresses and big.Int amounts.
+type DecodedTx struct {
+	Txid        string
+	Blockid     string
+	Initiator   string
+	AuthRequire []string
+	Desc        []byte
+	Coinbase    bool
+	Timestamp   int64
+	Kind        TxKind
+
+	Inputs           []*DecodedTxInput
+	Outputs          []*DecodedTxOutput
+	ContractRequests []*DecodedContractRequest
+
+	// Deploy parsed deploy args, only set for TxKindDeploy.
+	Deploy *DecodedDeploy
+	// Signers initiator signers then auth require signers.
+	Signers []*DecodedSigner
+
+	// Events contract events of the transaction, only set by block streaming.
+	Events []*ContractEvent
+
+	// Raw the original transaction.
+	Raw *pb.Transaction
+}
+
+// DecodedTxInput UTXO spent by the transaction.
+type DecodedTxInput struct {
+	RefTxid      string
+	RefOffset    int32
+	FromAddr     string
+	Amount       *big.Int
+	FrozenHeight int64
+}
+
+// DecodedTxOutput UTXO created by the transaction.
+type DecodedTxOutput struct {
+	ToAddr       string
+	Amount       *big.Int
+	FrozenHeight int64
+}
+
+// DecodedContractRequest contract invoke of the transaction.
+type DecodedContractRequest struct {
+	ModuleName   string
+	ContractName string
+	MethodName   string
+	Args         map[string][]byte
+	// Amount transfer to the contract, nil if no transfer.
+	Amount *big.Int
+}
+
+// DecodedDeploy args of xkernel Deploy or Upgrade.
+type DecodedDeploy struct {
+	AccountName  string
+	ContractName string
+	// ContractType contract module, such as wasm, native or evm.
+	ContractType string
+	// Runtime such as go, c or java, empty for evm.
+	Runtime string
+	// InitArgs args of the contract initialize method, evm args are decoded from the JSON input.
+	InitArgs map[string]string
+	// ABI evm contract ABI JSON.
+	ABI []byte
+	// Upgrade the request is Upgrade instead of Deploy.
+	Upgrade bool
+}
+
+// DecodedSigner signature of the transaction.
+type DecodedSigner struct {
+	Role string
+	// Address derived from PublicKey, empty if the public key is invalid.
+	Address   string
+	PublicKey string
+	Sign      string
+}
+
+// DecodeTx decode pb transaction.
+func DecodeTx(tx *pb.Transaction) *DecodedTx {
+	if tx == nil {
+		return nil
+	}
+
+	dtx := &DecodedTx{
+		Txid:             hex.EncodeToString(tx.GetTxid()),
+		Blockid:          hex.EncodeToString(tx.GetBlockid()),
+		Initiator:        tx.GetInitiator(),
+		AuthRequire:      tx.GetAuthRequire(),
+		Desc:             tx.GetDesc(),
+		Coinbase:         tx.GetCoinbase(),
+		Timestamp:        tx.GetTimestamp(),
+		Inputs:           make([]*DecodedTxInput, 0, len(tx.GetTxInputs())),
+		Outputs:          make([]*DecodedTxOutput, 0, len(tx.GetTxOutputs())),
+		ContractRequests: make([]*DecodedContractRequest, 0, len(tx.GetContractRequests())),
+		Raw:              tx,
+	}
+	for _, input := range tx.GetTxInputs() {
+		dtx.Inputs = append(dtx.Inputs, &DecodedTxInput{
+			RefTxid:      hex.EncodeToString(input.GetRefTxid()),
+			RefOffset:    input.GetRefOffset(),
+			FromAddr:     string(input.GetFromAddr()),
+			Amount:       new(big.Int).SetBytes(input.GetAmount()),
+			FrozenHeight: input.GetFrozenHeight(),
+		})
+	}
+	for _, output := range tx.GetTxOutputs() {
+		dtx.Outputs = append(dtx.Outputs, &DecodedTxOutput{
+			ToAddr:       string(output.GetToAddr()),
+			Amount:       new(big.Int).SetBytes(output.GetAmount()),
+			FrozenHeight: output.GetFrozenHeight(),
+		})
+	}
+	for _, req := range tx.GetContractRequests() {
+		dtx.ContractRequests = append(dtx.ContractRequests, decodeContractRequest(req))
+	}
+	for _, sign := range tx.GetInitiatorSigns() {
+		dtx.Signers = append(dtx.Signers, newDecodedSigner(SignerRoleInitiator, sign))
+	}
+	for _, sign := range tx.GetAuthRequireSigns() {
+		dtx.Signers = append(dtx.Signers, newDecodedSigner(SignerRoleAuthRequire, sign))
+	}
+
+	var kernelReq *DecodedContractRequest
+	dtx.Kind, kernelReq = classifyTx(dtx.ContractRequests)
+	if dtx.Kind == TxKindDeploy {
+		dtx.Deploy = decodeDeploy(kernelReq)
+	}
+	return dtx
+}
+
+// QueryDecodedTxByID query the tx by txID and decode it.
+func (x *XClient) QueryDecodedTxByID(txID string, opts ...QueryOption) (*DecodedTx, error) {
+	tx, err := x.queryTxByID(txID, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeTx(tx), nil
+}
+
+func decodeContractRequest(req *pb.InvokeRequest) *DecodedContractRequest {
+	dreq := &DecodedContractRequest{
+		ModuleName:   req.GetModuleName(),
+		ContractName: req.GetContractName(),
+		MethodName:   req.GetMethodName(),
+		Args:         req.GetArgs(),
+	}
+	if amount, ok := new(big.Int).SetString(req.GetAmount(), 10); ok {
+		dreq.Amount = amount
+	}
+	return dreq
+}
+
+// classifyTx xkernel requests decide the kind because reserved contract requests may come with them,
+// returns the xkernel request if any.
+func classifyTx(reqs []*DecodedContractRequest) (TxKind, *DecodedContractRequest) {
+	for _, req := range reqs {
+		if req.ModuleName != Xkernel3Module && req.ModuleName != XkernelModule {
+			continue
+		}
+		switch req.MethodName {
+		case XkernelDeployMethod, XkernelUpgradeMethod:
+			return TxKindDeploy, req
+		case XkernelNewAccountMethod:
+			return TxKindAccountCreation, req
+		case XkernelSetAccountACLMethod, XkernelSetMethodACLMethod:
+			return TxKindACLChange, req
+		}
+	}
+	if len(reqs) > 0 {
+		return TxKindInvoke, nil
+	}
+	return TxKindTransfer, nil
+}
+
+func decodeDeploy(req *DecodedContractRequest) *DecodedDeploy {
+	deploy := &DecodedDeploy{
+		AccountName:  string(req.Args[ArgAccountName]),
+		ContractName: string(req.Args[ArgContractName]),
+		InitArgs:     map[string]string{},
+		ABI:          req.Args[ArgContractAbi],
+		Upgrade:      req.MethodName == XkernelUpgradeMethod,
+	}
+	desc := &pb.WasmCodeDesc{}
+	if err := proto.Unmarshal(req.Args[ArgContractDesc], desc); err == nil {
+		deploy.ContractType = desc.GetContractType()
+		deploy.Runtime = desc.GetRuntime()
+	}
+
+	initArgs := map[string][]byte{}
+	if err := json.Unmarshal(req.Args[ArgInitArgs], &initArgs); err != nil {
+		return deploy
+	}
+	if string(initArgs[EvmJSONEncoded]) == EvmJSONEncodedTrue {
+		input := map[string]interface{}{}
+		if err := json.Unmarshal(initArgs["input"], &input); err == nil {
+			for k, v := range input {
+				deploy.InitArgs[k] = fmt.Sprint(v)
+			}
+			return deploy
+		}
+	}
+	for k, v := range initArgs {
+		deploy.InitArgs[k] = string(v)
+	}
+	return deploy
+}
+
+func newDecodedSigner(role string, sign *pb.SignatureInfo) *DecodedSigner {
+	signer := &DecodedSigner{
+		Role:      role,
+		PublicKey: sign.GetPublicKey(),
+		Sign:      hex.EncodeToString(sign.GetSign()),
+	}
+	cryptoClient := crypto.GetCryptoClient()
+	if publicKey, err := cryptoClient.GetEcdsaPublicKeyFromJsonStr(sign.GetPublicKey()); err == nil {
+		signer.Address, _ = cryptoClient.GetAddressFromPublicKey(publicKey)
+	}
+	return signer
+}
@@ -0,0 +1,177 @@
+package xuper
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+
+	"github.com/xuperchain/xuperchain/service/pb"
+)
+
+// cliTx transaction JSON the same as xchain-cli output.
+type cliTx struct {
+	Txid              string              `json:"txid"`
+	Blockid           string              `json:"blockid"`
+	TxInputs          []cliTxInput        `json:"txInputs"`
+	TxOutputs         []cliTxOutput       `json:"txOutputs"`
+	Desc              string              `json:"desc"`
+	Nonce             string              `json:"nonce"`
+	Timestamp         int64               `json:"timestamp"`
+	Version           int32               `json:"version"`
+	Autogen           bool                `json:"autogen"`
+	Coinbase          bool                `json:"coinbase"`
+	TxInputsExt       []cliTxInputExt     `json:"txInputsExt"`
+	TxOutputsExt      []cliTxOutputExt    `json:"txOutputsExt"`
+	ContractRequests  []*cliInvokeRequest `json:"contractRequests"`
+	Initiator         string              `json:"initiator"`
+	AuthRequire       []string            `json:"authRequire"`
+	InitiatorSigns    []cliSignatureInfo  `json:"initiatorSigns"`
+	AuthRequireSigns  []cliSignatureInfo  `json:"authRequireSigns"`
+	ReceivedTimestamp int64               `json:"receivedTimestamp"`
+	ModifyBlock       cliModifyBlock      `json:"modifyBlock"`
+}
+
+type cliTxInput struct {
+	RefTxid   string `json:"refTxid"`
+	RefOffset int32  `json:"refOffset"`
+	FromAddr  string `json:"fromAddr"`
+	Amount    string `json:"amount"`
+}
+
+type cliTxOutput struct {
+	Amount string `json:"amount"`
+	ToAddr string `json:"toAddr"`
+}
+
+type cliTxInputExt struct {
+	Bucket    string `json:"bucket"`
+	Key       string `json:"key"`
+	RefTxid   string `json:"refTxid"`
+	RefOffset int32  `json:"refOffset"`
+}
+
+type cliTxOutputExt struct {
+	Bucket string `json:"bucket"`
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+}
+
+type cliResourceLimit struct {
+	Type  string `json:"type"`
+	Limit int64  `json:"limit"`
+}
+
+type cliInvokeRequest struct {
+	ModuleName     string             `json:"moduleName"`
+	ContractName   string             `json:"contractName"`
+	MethodName     string             `json:"methodName"`
+	Args           map[string]string  `json:"args"`
+	ResourceLimits []cliResourceLimit `json:"resource_limits"`
+}
+
+type cliSignatureInfo struct {
+	PublicKey string `json:"publickey"`
+	Sign      string `json:"sign"`
+}
+
+type cliModifyBlock struct {
+	Marked          bool   `json:"marked"`
+	EffectiveHeight int64  `json:"effectiveHeight"`
+	EffectiveTxid   string `json:"effectiveTxid"`
+}
+
+// MarshalCliTx encode the transaction the same as xchain-cli `tx query` output.
+func MarshalCliTx(tx *pb.Transaction) ([]byte, error) {
+	if tx == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(newCliTx(tx))
+}
+
+// CliJSON encode the raw transaction like MarshalCliTx.
+func (d *DecodedTx) CliJSON() ([]byte, error) {
+	return MarshalCliTx(d.Raw)
+}
+
+func newCliTx(tx *pb.Transaction) *cliTx {
+	t := &cliTx{
+		Txid:              hex.EncodeToString(tx.Txid),
+		Blockid:           hex.EncodeToString(tx.Blockid),
+		Desc:              string(tx.Desc),
+		Nonce:             tx.Nonce,
+		Timestamp:         tx.Timestamp,
+		Version:           tx.Version,
+		Autogen:           tx.Autogen,
+		Coinbase:          tx.Coinbase,
+		Initiator:         tx.Initiator,
+		ReceivedTimestamp: tx.ReceivedTimestamp,
+	}
+	for _, input := range tx.TxInputs {
+		t.TxInputs = append(t.TxInputs, cliTxInput{
+			RefTxid:   hex.EncodeToString(input.RefTxid),
+			RefOffset: input.RefOffset,
+			FromAddr:  string(input.FromAddr),
+			Amount:    new(big.Int).SetBytes(input.Amount).String(),
+		})
+	}
+	for _, output := range tx.TxOutputs {
+		t.TxOutputs = append(t.TxOutputs, cliTxOutput{
+			Amount: new(big.Int).SetBytes(output.Amount).String(),
+			ToAddr: string(output.ToAddr),
+		})
+	}
+	for _, inputExt := range tx.TxInputsExt {
+		t.TxInputsExt = append(t.TxInputsExt, cliTxInputExt{
+			Bucket:    inputExt.Bucket,
+			Key:       string(inputExt.Key),
+			RefTxid:   hex.EncodeToString(inputExt.RefTxid),
+			RefOffset: inputExt.RefOffset,
+		})
+	}
+	for _, outputExt := range tx.TxOutputsExt {
+		t.TxOutputsExt = append(t.TxOutputsExt, cliTxOutputExt{
+			Bucket: outputExt.Bucket,
+			Key:    string(outputExt.Key),
+			Value:  string(outputExt.Value),
+		})
+	}
+	for _, req := range tx.ContractRequests {
+		cliReq := &cliInvokeRequest{
+			ModuleName:   req.ModuleName,
+			ContractName: req.ContractName,
+			MethodName:   req.MethodName,
+			Args:         map[string]string{},
+		}
+		for k, v := range req.Args {
+			cliReq.Args[k] = string(v)
+		}
+		for _, limit := range req.ResourceLimits {
+			cliReq.ResourceLimits = append(cliReq.ResourceLimits, cliResourceLimit{
+				Type:  limit.Type.String(),
+				Limit: limit.Limit,
+			})
+		}
+		t.ContractRequests = append(t.ContractRequests, cliReq)
+	}
+	t.AuthRequire = append(t.AuthRequire, tx.AuthRequire...)
+	for _, sign := range tx.InitiatorSigns {
+		t.InitiatorSigns = append(t.InitiatorSigns, cliSignatureInfo{
+			PublicKey: sign.PublicKey,
+			Sign:      hex.EncodeToString(sign.Sign),
+		})
+	}
+	for _, sign := range tx.AuthRequireSigns {
+		t.AuthRequireSigns = append(t.AuthRequireSigns, cliSignatureInfo{
+			PublicKey: sign.PublicKey,
+			Sign:      hex.EncodeToString(sign.Sign),
+		})
+	}
+	if tx.ModifyBlock != nil {
+		t.ModifyBlock = cliModifyBlock{
+			Marked:          tx.ModifyBlock.Marked,
+			EffectiveHeight: tx.ModifyBlock.EffectiveHeight,
+			EffectiveTxid:   tx.ModifyBlock.EffectiveTxid,
+		}
+	}
+	return t
+}
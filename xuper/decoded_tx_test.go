@@ -0,0 +1,116 @@
+package xuper
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/xuperchain/xuperchain/service/pb"
+
+	"github.com/superconsensus/matrix-sdk-go/v2/account"
+)
+
+func TestDecodeTxKind(t *testing.T) {
+	wasmArgs := generateDeployArgs(map[string]string{"creator": "alice"}, nil, []byte("code"), "wasm", GoRuntime, "XC1111111111111111@xuper", "counter")
+	evmArgs := generateDeployArgs(map[string]string{"name": "token"}, []byte("[]"), []byte("code"), EvmContractModule, "", "XC1111111111111111@xuper", "erc20")
+	reserved := &pb.InvokeRequest{ModuleName: "wasm", ContractName: "identity", MethodName: "verify"}
+
+	cases := []struct {
+		name string
+		reqs []*pb.InvokeRequest
+		kind TxKind
+	}{
+		{"transfer", nil, TxKindTransfer},
+		{"invoke", []*pb.InvokeRequest{{ModuleName: "wasm", ContractName: "counter", MethodName: "increase"}}, TxKindInvoke},
+		{"deploy", []*pb.InvokeRequest{reserved, {ModuleName: Xkernel3Module, MethodName: XkernelDeployMethod, Args: wasmArgs}}, TxKindDeploy},
+		{"account", []*pb.InvokeRequest{{ModuleName: Xkernel3Module, MethodName: XkernelNewAccountMethod}}, TxKindAccountCreation},
+		{"account acl", []*pb.InvokeRequest{{ModuleName: Xkernel3Module, MethodName: XkernelSetAccountACLMethod}}, TxKindACLChange},
+		{"method acl", []*pb.InvokeRequest{{ModuleName: XkernelModule, MethodName: XkernelSetMethodACLMethod}}, TxKindACLChange},
+	}
+	for _, c := range cases {
+		tx := DecodeTx(&pb.Transaction{ContractRequests: c.reqs})
+		if tx.Kind != c.kind {
+			t.Errorf("DecodeTx %s kind assert failed: %s", c.name, tx.Kind)
+		}
+		if (tx.Deploy != nil) != (c.kind == TxKindDeploy) {
+			t.Errorf("DecodeTx %s deploy assert failed", c.name)
+		}
+	}
+
+	deploy := DecodeTx(&pb.Transaction{ContractRequests: []*pb.InvokeRequest{{ModuleName: Xkernel3Module, MethodName: XkernelDeployMethod, Args: wasmArgs}}}).Deploy
+	if deploy.ContractName != "counter" || deploy.AccountName != "XC1111111111111111@xuper" || deploy.ContractType != "wasm" ||
+		deploy.Runtime != GoRuntime || deploy.InitArgs["creator"] != "alice" || deploy.Upgrade {
+		t.Errorf("DecodeTx wasm deploy assert failed: %+v", deploy)
+	}
+	deploy = DecodeTx(&pb.Transaction{ContractRequests: []*pb.InvokeRequest{{ModuleName: Xkernel3Module, MethodName: XkernelUpgradeMethod, Args: evmArgs}}}).Deploy
+	if deploy.ContractType != EvmContractModule || deploy.InitArgs["name"] != "token" || string(deploy.ABI) != "[]" || !deploy.Upgrade {
+		t.Errorf("DecodeTx evm deploy assert failed: %+v", deploy)
+	}
+}
+
+func TestDecodeTxSigners(t *testing.T) {
+	alice, err := account.CreateAccount(1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := DecodeTx(&pb.Transaction{
+		InitiatorSigns:   []*pb.SignatureInfo{{PublicKey: alice.PublicKey, Sign: []byte{1}}},
+		AuthRequireSigns: []*pb.SignatureInfo{{PublicKey: "invalid", Sign: []byte{2}}},
+	})
+	if len(tx.Signers) != 2 {
+		t.Fatal("DecodeTx signers count assert failed", len(tx.Signers))
+	}
+	if s := tx.Signers[0]; s.Role != SignerRoleInitiator || s.Address != alice.Address || s.Sign != "01" {
+		t.Errorf("DecodeTx initiator signer assert failed: %+v", s)
+	}
+	if s := tx.Signers[1]; s.Role != SignerRoleAuthRequire || s.Address != "" || s.Sign != "02" {
+		t.Errorf("DecodeTx auth require signer assert failed: %+v", s)
+	}
+}
+
+func TestDecodedTxCliJSON(t *testing.T) {
+	tx := newMockTx("0a0b", "alice", "bob", 1000)
+	tx.InitiatorSigns = []*pb.SignatureInfo{{PublicKey: "pk", Sign: []byte{0xff}}}
+	tx.TxOutputsExt = []*pb.TxOutputExt{{Bucket: "counter", Key: []byte("k"), Value: []byte("v")}}
+	tx.ContractRequests[0].Args = map[string][]byte{"key": []byte("v")}
+	data, err := DecodeTx(tx).CliJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]interface{}{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["txid"] != "0a0b" || got["initiator"] != "alice" || got["txInputsExt"] != nil {
+		t.Errorf("CliJSON fields assert failed: %s", data)
+	}
+	output := got["txOutputs"].([]interface{})[0].(map[string]interface{})
+	if output["amount"] != big.NewInt(1000).String() || output["toAddr"] != "bob" {
+		t.Errorf("CliJSON output assert failed: %v", output)
+	}
+	req := got["contractRequests"].([]interface{})[0].(map[string]interface{})
+	if req["moduleName"] != "wasm" || req["args"].(map[string]interface{})["key"] != "v" {
+		t.Errorf("CliJSON contract request assert failed: %v", req)
+	}
+	sign := got["initiatorSigns"].([]interface{})[0].(map[string]interface{})
+	if sign["publickey"] != "pk" || sign["sign"] != "ff" {
+		t.Errorf("CliJSON sign assert failed: %v", sign)
+	}
+	if _, ok := got["modifyBlock"].(map[string]interface{}); !ok {
+		t.Errorf("CliJSON modifyBlock assert failed: %s", data)
+	}
+
+	// the default encoding keeps the decoded fields.
+	data, err = json.Marshal(DecodeTx(tx))
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := map[string]interface{}{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded["Kind"] != string(TxKindInvoke) || decoded["Signers"] == nil || decoded["Txid"] != "0a0b" {
+		t.Errorf("DecodedTx JSON assert failed: %s", data)
+	}
+}
@@ -13,11 +13,16 @@ type Watcher struct {
 	exit              chan<- struct{}
 
 	opt *blockEventOption
+	// err stream error, set before FilteredBlockChan closed.
+	err error
 }
 
 func initEventOpts(opts ...BlockEventOption) (*blockEventOption, error) {
 	opt := &blockEventOption{
 		blockChanBufferSize: 100, // default 100.
+		replayPageSize:      defaultReplayPageSize,
+		confirmationDepth:   defaultConfirmationDepth,
+		txFetchWorkers:      defaultTxFetchWorkers,
 		blockFilter: &pb.BlockFilter{
 			Bcname: "xuper", // default xuper.
 		},
@@ -92,6 +97,15 @@ type blockEventOption struct {
 
 	blockChanBufferSize uint
 	skipEmptyTx         bool
+
+	eventDecoder EventDecoder
+
+	replayPageSize int64
+	replayFollow   bool
+
+	confirmationDepth int64
+
+	txFetchWorkers int
 }
 
 // WithBlockChanBufferSize block event block channel size, default 100.
@@ -113,6 +127,55 @@ func WithSkipEmplyTx() BlockEventOption {
 	}
 }
 
+// WithEventDecoder decoder of contract event body, used by SubscribeContractEvents.
+func WithEventDecoder(decoder EventDecoder) BlockEventOption {
+	return func(f *blockEventOption) error {
+		f.eventDecoder = decoder
+		return nil
+	}
+}
+
+// WithReplayPageSize blocks count of each subscription when ReplayEvents, default 1000.
+func WithReplayPageSize(size int64) BlockEventOption {
+	return func(f *blockEventOption) error {
+		if size <= 0 {
+			return errors.New("Invalid size for replay page size")
+		}
+		f.replayPageSize = size
+		return nil
+	}
+}
+
+// WithReplayFollow ReplayEvents keeps following new blocks after the range replayed.
+func WithReplayFollow() BlockEventOption {
+	return func(f *blockEventOption) error {
+		f.replayFollow = true
+		return nil
+	}
+}
+
+// WithConfirmationDepth blocks count on top of a block before WatchChainEvents confirms it, default 3.
+func WithConfirmationDepth(depth int64) BlockEventOption {
+	return func(f *blockEventOption) error {
+		if depth < 0 {
+			return errors.New("Invalid confirmation depth")
+		}
+		f.confirmationDepth = depth
+		return nil
+	}
+}
+
+// WithTxFetchWorkers max concurrent QueryTxByID when WatchFullBlocks, default 8.
+func WithTxFetchWorkers(n int) BlockEventOption {
+	return func(f *blockEventOption) error {
+		if n <= 0 {
+			return errors.New("Invalid tx fetch workers")
+		}
+		f.txFetchWorkers = n
+		return nil
+	}
+}
+
 // WithBlockEventBcname blockchain name.
 func WithBlockEventBcname(name string) BlockEventOption {
 	return func(f *blockEventOption) error {
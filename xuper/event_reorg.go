@@ -0,0 +1,245 @@
+package xuper
+
+import (
+	"context"
+	"encoding/hex"
+	"strconv"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/xuperchain/xuperchain/service/pb"
+)
+
+const defaultConfirmationDepth = 3
+
+// Types of ChainEvent.
+const (
+	// ChainEventBlockAdded block arrived, it may still be orphaned.
+	ChainEventBlockAdded = "added"
+	// ChainEventBlockConfirmed block has confirmation depth blocks on top of it, it is final.
+	ChainEventBlockConfirmed = "confirmed"
+	// ChainEventBlockReverted block added before is orphaned by a fork, undo what it did.
+	ChainEventBlockReverted = "reverted"
+)
+
+// ChainEvent block event of WatchChainEvents.
+type ChainEvent struct {
+	// Type ChainEventBlockAdded, ChainEventBlockConfirmed or ChainEventBlockReverted, empty for error.
+	Type  string
+	Block *FilteredBlock
+	// Err stream or query error, it is the last event.
+	Err error
+}
+
+// ChainWatcher block events stream with reorg detection.
+type ChainWatcher struct {
+	// C closed after error, context done or Close called.
+	C <-chan *ChainEvent
+
+	cancel context.CancelFunc
+	once   sync.Once
+}
+
+// Close stop the watcher.
+func (w *ChainWatcher) Close() {
+	w.once.Do(func() {
+		if w.cancel != nil {
+			w.cancel()
+		}
+	})
+}
+
+// WatchChainEvents watch blocks like WatchBlockEvent and detect chain reorganization.
+// The block arrived is checked against QueryBlockByHeight, unconfirmed blocks are checked only if it is not
+// in the trunk or does not link to the block before it. Orphaned blocks are reverted from the highest one
+// and blocks from the fork height are received again. A block is confirmed when WithConfirmationDepth blocks
+// are on top of it, or when the stream of a bounded WithBlockRange ends.
+//
+// Parameters:
+//   - `ctx` : The watcher is stopped when ctx done.
+//   - `opts`: Block event options, such as WithBlockEventBcname and WithConfirmationDepth.
+func (x *XClient) WatchChainEvents(ctx context.Context, opts ...BlockEventOption) (*ChainWatcher, error) {
+	opt, err := initEventOpts(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	eventChan := make(chan *ChainEvent, opt.blockChanBufferSize)
+	watcher := &ChainWatcher{C: eventChan, cancel: cancel}
+
+	send := func(event *ChainEvent) bool {
+		select {
+		case eventChan <- event:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	go func() {
+		defer close(eventChan)
+		defer watcher.Close()
+
+		tracker := &reorgTracker{depth: opt.confirmationDepth}
+		watchOpts := opts
+		for {
+			forkHeight, err := x.watchCanonicalBlocks(ctx, tracker, send, opt.blockFilter.GetBcname(), watchOpts...)
+			if err != nil {
+				send(&ChainEvent{Err: err})
+				return
+			}
+			if forkHeight < 0 {
+				return
+			}
+			watchOpts = append(opts, WithBlockRange(strconv.FormatInt(forkHeight, 10), opt.blockFilter.GetRange().GetEnd()))
+		}
+	}()
+	return watcher, nil
+}
+
+// watchCanonicalBlocks returns the fork height to watch again from, or -1 if the stream ends.
+func (x *XClient) watchCanonicalBlocks(ctx context.Context, tracker *reorgTracker, send func(*ChainEvent) bool, bcname string, opts ...BlockEventOption) (int64, error) {
+	watcher, err := x.watchBlockEvent(ctx, opts...)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		watcher.Close()
+		// unblock the watcher goroutine.
+		for range watcher.FilteredBlockChan {
+		}
+	}()
+
+	canonicalBlock := func(height int64) (*pb.InternalBlock, error) {
+		block, err := x.queryBlockByHeight(height, WithQueryBcname(bcname))
+		if err != nil {
+			return nil, errors.Wrapf(err, "query block %d failed", height)
+		}
+		return block.GetBlock(), nil
+	}
+
+	for block := range watcher.FilteredBlockChan {
+		// node sends a height again, blocks received before are replaced.
+		for _, reverted := range tracker.revertFrom(block.BlockHeight) {
+			if !send(&ChainEvent{Type: ChainEventBlockReverted, Block: reverted}) {
+				return 0, ctx.Err()
+			}
+		}
+
+		tracker.add(block)
+		if !send(&ChainEvent{Type: ChainEventBlockAdded, Block: block}) {
+			return 0, ctx.Err()
+		}
+
+		forkHeight, err := tracker.findFork(canonicalBlock)
+		if err != nil {
+			return 0, err
+		}
+		if forkHeight >= 0 {
+			for _, reverted := range tracker.revertFrom(forkHeight) {
+				if !send(&ChainEvent{Type: ChainEventBlockReverted, Block: reverted}) {
+					return 0, ctx.Err()
+				}
+			}
+			return forkHeight, nil
+		}
+
+		for _, confirmed := range tracker.confirm() {
+			if !send(&ChainEvent{Type: ChainEventBlockConfirmed, Block: confirmed}) {
+				return 0, ctx.Err()
+			}
+		}
+	}
+
+	if ctx.Err() != nil {
+		return 0, ctx.Err()
+	}
+	if watcher.err != nil {
+		return 0, watcher.err
+	}
+
+	// no more blocks will be on top of those in the confirmation window.
+	for _, confirmed := range tracker.flush() {
+		if !send(&ChainEvent{Type: ChainEventBlockConfirmed, Block: confirmed}) {
+			return 0, ctx.Err()
+		}
+	}
+	return -1, nil
+}
+
+// reorgTracker unconfirmed blocks ordered by height.
+type reorgTracker struct {
+	depth  int64
+	recent []*FilteredBlock
+}
+
+func (r *reorgTracker) add(block *FilteredBlock) {
+	r.recent = append(r.recent, block)
+}
+
+// revertFrom remove blocks whose height >= height, returns them from the highest one.
+func (r *reorgTracker) revertFrom(height int64) []*FilteredBlock {
+	reverted := []*FilteredBlock{}
+	for len(r.recent) > 0 && r.recent[len(r.recent)-1].BlockHeight >= height {
+		reverted = append(reverted, r.recent[len(r.recent)-1])
+		r.recent = r.recent[:len(r.recent)-1]
+	}
+	return reverted
+}
+
+// findFork returns the lowest height whose block is not canonical, or -1 if all are canonical.
+// Only the newest block is queried if it is canonical and links to the block before it.
+func (r *reorgTracker) findFork(canonicalBlock func(height int64) (*pb.InternalBlock, error)) (int64, error) {
+	if len(r.recent) == 0 {
+		return -1, nil
+	}
+	newest := r.recent[len(r.recent)-1]
+	block, err := canonicalBlock(newest.BlockHeight)
+	if err != nil {
+		return 0, err
+	}
+	if hex.EncodeToString(block.GetBlockid()) == newest.Blockid {
+		if len(r.recent) == 1 {
+			return -1, nil
+		}
+		prev := r.recent[len(r.recent)-2]
+		if prev.BlockHeight == newest.BlockHeight-1 && hex.EncodeToString(block.GetPreHash()) == prev.Blockid {
+			return -1, nil
+		}
+	}
+
+	// walk back for the fork.
+	for _, b := range r.recent {
+		block, err := canonicalBlock(b.BlockHeight)
+		if err != nil {
+			return 0, err
+		}
+		if hex.EncodeToString(block.GetBlockid()) != b.Blockid {
+			return b.BlockHeight, nil
+		}
+	}
+	return -1, nil
+}
+
+// confirm remove and returns blocks which have depth blocks on top of them.
+func (r *reorgTracker) confirm() []*FilteredBlock {
+	if len(r.recent) == 0 {
+		return nil
+	}
+	tip := r.recent[len(r.recent)-1].BlockHeight
+	n := 0
+	for n < len(r.recent) && tip-r.recent[n].BlockHeight >= r.depth {
+		n++
+	}
+	confirmed := r.recent[:n]
+	r.recent = append([]*FilteredBlock{}, r.recent[n:]...)
+	return confirmed
+}
+
+// flush remove and returns all blocks.
+func (r *reorgTracker) flush() []*FilteredBlock {
+	flushed := r.recent
+	r.recent = nil
+	return flushed
+}
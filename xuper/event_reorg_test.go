@@ -0,0 +1,94 @@
+package xuper
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/xuperchain/xuperchain/service/pb"
+	"google.golang.org/grpc"
+)
+
+// mockBlockXClient mock node with canonical block ids by height.
+type mockBlockXClient struct {
+	*MockXClient
+	ids     map[int64]string
+	queries int
+}
+
+func (m *mockBlockXClient) GetBlockByHeight(ctx context.Context, in *pb.BlockHeight, opts ...grpc.CallOption) (*pb.Block, error) {
+	m.queries++
+	id, _ := hex.DecodeString(m.ids[in.GetHeight()])
+	preHash, _ := hex.DecodeString(m.ids[in.GetHeight()-1])
+	return &pb.Block{
+		Header:  newHeader(),
+		Blockid: id,
+		Block:   &pb.InternalBlock{Blockid: id, PreHash: preHash, Height: in.GetHeight()},
+	}, nil
+}
+
+func TestWatchChainEvents(t *testing.T) {
+	block := func(height int64, id string) *pb.FilteredBlock {
+		return &pb.FilteredBlock{Bcname: "xuper", Blockid: id, BlockHeight: height}
+	}
+	esc := &mockEventServiceClient{
+		streams: [][]*pb.FilteredBlock{
+			{block(1, "a1"), block(2, "a2"), block(3, "a3")},
+			{block(3, "b3"), block(4, "b4")},
+		},
+	}
+	mock := &mockBlockXClient{MockXClient: &MockXClient{}, ids: map[int64]string{1: "a1", 2: "a2", 3: "b3", 4: "b4"}}
+	xc := &XClient{
+		xc:  mock,
+		esc: esc,
+	}
+
+	watcher, err := xc.WatchChainEvents(context.Background(), WithConfirmationDepth(1), WithBlockRange("1", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer watcher.Close()
+
+	got := []string{}
+	for event := range watcher.C {
+		if event.Err != nil {
+			t.Fatal(event.Err)
+		}
+		got = append(got, event.Type+":"+event.Block.Blockid)
+	}
+	expect := []string{
+		"added:a1", "added:a2", "confirmed:a1",
+		"added:a3", "reverted:a3",
+		"added:b3", "confirmed:a2", "added:b4", "confirmed:b3",
+		// the bounded range ends.
+		"confirmed:b4",
+	}
+	if len(got) != len(expect) {
+		t.Fatalf("WatchChainEvents expect %v, got %v", expect, got)
+	}
+	for i := range expect {
+		if got[i] != expect[i] {
+			t.Fatalf("WatchChainEvents expect %v, got %v", expect, got)
+		}
+	}
+	if esc.lastFilter().GetRange().GetStart() != "3" {
+		t.Error("WatchChainEvents resubscribe assert failed", esc.lastFilter().GetRange())
+	}
+	// one query per block, the fork at a3 walks back a2 and a3.
+	if mock.queries != 7 {
+		t.Error("WatchChainEvents queries assert failed", mock.queries)
+	}
+}
+
+func TestReorgTracker(t *testing.T) {
+	tracker := &reorgTracker{depth: 2}
+	for i := int64(1); i <= 4; i++ {
+		tracker.add(&FilteredBlock{BlockHeight: i})
+	}
+	if confirmed := tracker.confirm(); len(confirmed) != 2 || confirmed[1].BlockHeight != 2 {
+		t.Error("reorgTracker confirm assert failed", confirmed)
+	}
+	if reverted := tracker.revertFrom(3); len(reverted) != 2 || reverted[0].BlockHeight != 4 || len(tracker.recent) != 0 {
+		t.Error("reorgTracker revert assert failed", reverted)
+	}
+}
@@ -0,0 +1,124 @@
+package xuper
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+const defaultReplayPageSize = 1000
+
+// ReplayMessage one message of ReplayEvents.
+type ReplayMessage struct {
+	// Block filtered block, nil for completion marker and error.
+	Block *FilteredBlock
+	// Completed true for the marker sent once all blocks in the replay range are delivered.
+	Completed bool
+	// Live true if the block is received after the replay range, only when WithReplayFollow.
+	Live bool
+	// Err stream error, it is the last message.
+	Err error
+}
+
+// EventReplay historical block events stream of ReplayEvents.
+type EventReplay struct {
+	// C closed after the completion marker, or error, or context done, or Close called.
+	C <-chan *ReplayMessage
+
+	cancel context.CancelFunc
+	once   sync.Once
+}
+
+// Close stop the replay.
+func (r *EventReplay) Close() {
+	r.once.Do(r.cancel)
+}
+
+// ReplayEvents replay block events of [fromHeight, toHeight] page by page, memory is bounded by
+// WithBlockChanBufferSize, a completion marker is sent after toHeight.
+// With WithReplayFollow it then follows new blocks from toHeight+1, no block is missed at the handover.
+//
+// Parameters:
+//   - `ctx`       : The replay is stopped when ctx done.
+//   - `fromHeight`: The first block height.
+//   - `toHeight`  : The last block height, included.
+//   - `opts`      : Block event filter options such as WithContract, and WithReplayPageSize, WithReplayFollow.
+func (x *XClient) ReplayEvents(ctx context.Context, fromHeight, toHeight int64, opts ...BlockEventOption) (*EventReplay, error) {
+	if fromHeight < 0 || toHeight < fromHeight {
+		return nil, errors.Errorf("invalid replay range [%d, %d]", fromHeight, toHeight)
+	}
+	opt, err := initEventOpts(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	msgChan := make(chan *ReplayMessage, opt.blockChanBufferSize)
+	replay := &EventReplay{C: msgChan, cancel: cancel}
+
+	send := func(msg *ReplayMessage) bool {
+		select {
+		case msgChan <- msg:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	go func() {
+		defer close(msgChan)
+		defer replay.Close()
+
+		for start := fromHeight; start <= toHeight; start += opt.replayPageSize {
+			end := start + opt.replayPageSize
+			if end > toHeight+1 {
+				end = toHeight + 1
+			}
+			if err := x.forwardBlocks(ctx, send, false, append(opts, WithBlockRange(strconv.FormatInt(start, 10), strconv.FormatInt(end, 10)))...); err != nil {
+				send(&ReplayMessage{Err: err})
+				return
+			}
+		}
+		if !send(&ReplayMessage{Completed: true}) || !opt.replayFollow {
+			return
+		}
+
+		// empty end means following the chain.
+		if err := x.forwardBlocks(ctx, send, true, append(opts, WithBlockRange(strconv.FormatInt(toHeight+1, 10), ""))...); err != nil {
+			send(&ReplayMessage{Err: err})
+		}
+	}()
+	return replay, nil
+}
+
+// forwardBlocks subscribe blocks and send them until the stream ends, returns the stream error.
+func (x *XClient) forwardBlocks(ctx context.Context, send func(*ReplayMessage) bool, live bool, opts ...BlockEventOption) error {
+	watcher, err := x.watchBlockEvent(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		watcher.Close()
+		// unblock the watcher goroutine.
+		for range watcher.FilteredBlockChan {
+		}
+	}()
+
+	for block := range watcher.FilteredBlockChan {
+		if !send(&ReplayMessage{Block: block, Live: live}) {
+			return ctx.Err()
+		}
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if watcher.err != nil {
+		return watcher.err
+	}
+	if live {
+		return errors.New("block event stream closed")
+	}
+	return nil
+}
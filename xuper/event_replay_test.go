@@ -0,0 +1,76 @@
+package xuper
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/xuperchain/xuperchain/service/pb"
+)
+
+func TestReplayEvents(t *testing.T) {
+	esc := &mockEventServiceClient{hold: true}
+	for i := int64(0); i < 10; i++ {
+		esc.blocks = append(esc.blocks, &pb.FilteredBlock{Bcname: "xuper", Blockid: "b" + strconv.FormatInt(i, 10), BlockHeight: i})
+	}
+	xc := &XClient{esc: esc}
+
+	replay, err := xc.ReplayEvents(context.Background(), 2, 6, WithReplayPageSize(2), WithContract("counter"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	heights := []int64{}
+	completed := false
+	for msg := range replay.C {
+		switch {
+		case msg.Err != nil:
+			t.Fatal(msg.Err)
+		case msg.Completed:
+			completed = true
+		case completed:
+			t.Error("ReplayEvents block after completion marker", msg.Block.BlockHeight)
+		default:
+			heights = append(heights, msg.Block.BlockHeight)
+		}
+	}
+	if !completed || len(heights) != 5 || heights[0] != 2 || heights[4] != 6 {
+		t.Error("ReplayEvents range assert failed", heights, completed)
+	}
+	if len(esc.filters) != 3 || esc.lastFilter().GetRange().GetStart() != "6" || esc.lastFilter().GetRange().GetEnd() != "7" ||
+		esc.lastFilter().GetContract() != "counter" {
+		t.Error("ReplayEvents pages assert failed", esc.filters)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	replay, err = xc.ReplayEvents(ctx, 5, 6, WithReplayFollow())
+	if err != nil {
+		t.Fatal(err)
+	}
+	next := int64(5)
+	completed = false
+	for msg := range replay.C {
+		if msg.Err != nil {
+			t.Fatal(msg.Err)
+		}
+		if msg.Completed {
+			completed = true
+			continue
+		}
+		if msg.Block.BlockHeight != next || msg.Live != (next > 6) || msg.Live != completed {
+			t.Fatal("ReplayEvents follow assert failed", msg.Block.BlockHeight, msg.Live)
+		}
+		next++
+		if next == 10 {
+			replay.Close()
+			break
+		}
+	}
+	if next != 10 {
+		t.Error("ReplayEvents follow handover assert failed", next)
+	}
+
+	if _, err := xc.ReplayEvents(context.Background(), 6, 2); err == nil {
+		t.Error("ReplayEvents invalid range assert failed")
+	}
+}
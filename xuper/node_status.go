@@ -0,0 +1,165 @@
+package xuper
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/xuperchain/xuperchain/service/pb"
+)
+
+// HealthState node state compared with other nodes.
+type HealthState string
+
+// States of NodeHealth.
+const (
+	// HealthSynced the node tip is within max lag of the highest tip.
+	HealthSynced HealthState = "synced"
+	// HealthLagging the node tip is more than max lag behind the highest tip.
+	HealthLagging HealthState = "lagging"
+	// HealthUnreachable the chain status query of the node failed.
+	HealthUnreachable HealthState = "unreachable"
+)
+
+// NodeStatus status of a node and one of its chains.
+type NodeStatus struct {
+	Bcname      string
+	TipHeight   int64
+	TipBlockid  string
+	RootBlockid string
+	// TrunkHeight height of the longest chain in the ledger meta, the same as TipHeight unless the tip is switching.
+	TrunkHeight int64
+	// BranchBlockids tips of branches the node knows besides the trunk.
+	BranchBlockids     []string
+	IrreversibleHeight int64
+	// UtxoTotal total amount of all UTXOs, nil if the node returns none.
+	UtxoTotal      *big.Int
+	UnconfirmedTxs int64
+	AvgDelay       int64
+	NetURL         string
+	Peers          []string
+	ConsensusName  string
+	// ConsensusVersion version of the consensus plugin, not of the node software.
+	ConsensusVersion string
+	// PartialErrs errors of the queries besides the chain status, the fields they fill are left empty.
+	PartialErrs []error
+}
+
+// NodeHealth health of a node.
+type NodeHealth struct {
+	State HealthState
+	// Status nil if State is HealthUnreachable.
+	Status *NodeStatus
+	// Lag blocks behind the highest tip of the compared nodes.
+	Lag int64
+	Err error
+}
+
+// QueryNodeStatus query the chain status, the system status, the net URL and the consensus status of the node.
+// Only the chain status query failure is returned as error, others are in NodeStatus.PartialErrs.
+func (x *XClient) QueryNodeStatus(opts ...QueryOption) (*NodeStatus, error) {
+	opt, err := initQueryOpts(opts...)
+	if err != nil {
+		return nil, err
+	}
+	bcname := getBCname(opt)
+
+	bcs, err := x.queryBlockChainStatus(opts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "query block chain status failed")
+	}
+
+	var partialErrs []error
+	ss, err := x.querySystemStatus(opts...)
+	if err != nil {
+		partialErrs = append(partialErrs, errors.Wrap(err, "query system status failed"))
+	}
+	netURL, err := x.queryNetURL(opts...)
+	if err != nil {
+		partialErrs = append(partialErrs, errors.Wrap(err, "query net URL failed"))
+	}
+	consensus, err := x.xc.GetConsensusStatus(context.TODO(), &pb.ConsensusStatRequest{Bcname: bcname})
+	if err == nil && consensus.GetHeader().GetError() != pb.XChainErrorEnum_SUCCESS {
+		err = errors.New(consensus.GetHeader().GetError().String())
+	}
+	if err != nil {
+		consensus = nil
+		partialErrs = append(partialErrs, errors.Wrap(err, "query consensus status failed"))
+	}
+
+	status := &NodeStatus{
+		Bcname:             bcname,
+		TipHeight:          bcs.GetBlock().GetHeight(),
+		TipBlockid:         hex.EncodeToString(bcs.GetBlock().GetBlockid()),
+		RootBlockid:        hex.EncodeToString(bcs.GetMeta().GetRootBlockid()),
+		TrunkHeight:        bcs.GetMeta().GetTrunkHeight(),
+		BranchBlockids:     bcs.GetBranchBlockid(),
+		IrreversibleHeight: bcs.GetUtxoMeta().GetIrreversibleBlockHeight(),
+		UnconfirmedTxs:     bcs.GetUtxoMeta().GetUnconfirmTxAmount(),
+		AvgDelay:           bcs.GetUtxoMeta().GetAvgDelay(),
+		NetURL:             netURL,
+		Peers:              ss.GetSystemsStatus().GetPeerUrls(),
+		ConsensusName:      consensus.GetConsensusName(),
+		ConsensusVersion:   consensus.GetVersion(),
+	}
+	if total := bcs.GetUtxoMeta().GetUtxoTotal(); total != "" {
+		if utxoTotal, ok := new(big.Int).SetString(total, 10); ok {
+			status.UtxoTotal = utxoTotal
+		} else {
+			partialErrs = append(partialErrs, errors.Errorf("invalid UTXO total %s", total))
+		}
+	}
+	status.PartialErrs = partialErrs
+	return status, nil
+}
+
+// Health query the node and peers status, the node is lagging if its tip is more than maxLag blocks
+// behind the highest tip of them.
+//
+// Parameters:
+//   - `peers` : Other nodes of the same chain.
+//   - `maxLag`: Blocks the node may fall behind and still be synced.
+//   - `opts`  : Query options, such as WithQueryBcname.
+func (x *XClient) Health(peers []*XClient, maxLag int64, opts ...QueryOption) *NodeHealth {
+	return CheckHealth(append([]*XClient{x}, peers...), maxLag, opts...)[0]
+}
+
+// CheckHealth query status of nodes concurrently and classify each of them like XClient.Health,
+// a node is unreachable only if its chain status query fails. Results keep the order of clients.
+func CheckHealth(clients []*XClient, maxLag int64, opts ...QueryOption) []*NodeHealth {
+	results := make([]*NodeHealth, len(clients))
+	var wg sync.WaitGroup
+	for i, client := range clients {
+		wg.Add(1)
+		go func(i int, client *XClient) {
+			defer wg.Done()
+			status, err := client.QueryNodeStatus(opts...)
+			if err != nil {
+				results[i] = &NodeHealth{State: HealthUnreachable, Err: err}
+				return
+			}
+			results[i] = &NodeHealth{Status: status}
+		}(i, client)
+	}
+	wg.Wait()
+
+	var highest int64
+	for _, r := range results {
+		if r.Status != nil && r.Status.TipHeight > highest {
+			highest = r.Status.TipHeight
+		}
+	}
+	for _, r := range results {
+		if r.Status == nil {
+			continue
+		}
+		r.Lag = highest - r.Status.TipHeight
+		r.State = HealthSynced
+		if r.Lag > maxLag {
+			r.State = HealthLagging
+		}
+	}
+	return results
+}
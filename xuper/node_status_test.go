@@ -0,0 +1,113 @@
+package xuper
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/xuperchain/xuperchain/service/pb"
+	"google.golang.org/grpc"
+)
+
+// mockStatusXClient mock node at height, unreachable if down, without consensus status if noConsensus.
+type mockStatusXClient struct {
+	*MockXClient
+	height      int64
+	down        bool
+	noConsensus bool
+}
+
+func (m *mockStatusXClient) GetBlockChainStatus(ctx context.Context, in *pb.BCStatus, opts ...grpc.CallOption) (*pb.BCStatus, error) {
+	if m.down {
+		return nil, errors.New("connection refused")
+	}
+	return &pb.BCStatus{
+		Header:        newHeader(),
+		Bcname:        in.GetBcname(),
+		Meta:          &pb.LedgerMeta{RootBlockid: []byte{0x01}, TipBlockid: []byte{0xaa}, TrunkHeight: m.height},
+		Block:         &pb.InternalBlock{Blockid: []byte{0xaa}, Height: m.height},
+		UtxoMeta:      &pb.UtxoMeta{UtxoTotal: "100000000000000000000", UnconfirmTxAmount: 2, IrreversibleBlockHeight: m.height - 3},
+		BranchBlockid: []string{"bb"},
+	}, nil
+}
+
+func (m *mockStatusXClient) GetSystemStatus(ctx context.Context, in *pb.CommonIn, opts ...grpc.CallOption) (*pb.SystemsStatusReply, error) {
+	return &pb.SystemsStatusReply{
+		Header:        newHeader(),
+		SystemsStatus: &pb.SystemsStatus{PeerUrls: []string{"/ip4/127.0.0.1/tcp/47102/p2p/peer"}},
+	}, nil
+}
+
+func (m *mockStatusXClient) GetNetURL(ctx context.Context, in *pb.CommonIn, opts ...grpc.CallOption) (*pb.RawUrl, error) {
+	return &pb.RawUrl{Header: newHeader(), RawUrl: "/ip4/127.0.0.1/tcp/47101/p2p/self"}, nil
+}
+
+func (m *mockStatusXClient) GetConsensusStatus(ctx context.Context, in *pb.ConsensusStatRequest, opts ...grpc.CallOption) (*pb.ConsensusStatus, error) {
+	if m.noConsensus {
+		return nil, errors.New("unknown method GetConsensusStatus")
+	}
+	return &pb.ConsensusStatus{Header: newHeader(), ConsensusName: "tdpos", Version: "1"}, nil
+}
+
+func TestQueryNodeStatus(t *testing.T) {
+	xc := &XClient{xc: &mockStatusXClient{MockXClient: &MockXClient{}, height: 10}}
+	status, err := xc.QueryNodeStatus(WithQueryBcname("hc"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Bcname != "hc" || status.TipHeight != 10 || status.TipBlockid != "aa" || status.RootBlockid != "01" ||
+		status.TrunkHeight != 10 || len(status.BranchBlockids) != 1 || status.IrreversibleHeight != 7 || status.UnconfirmedTxs != 2 {
+		t.Errorf("QueryNodeStatus chain assert failed: %+v", status)
+	}
+	if status.UtxoTotal.String() != "100000000000000000000" {
+		t.Error("QueryNodeStatus UTXO total assert failed", status.UtxoTotal)
+	}
+	if status.NetURL != "/ip4/127.0.0.1/tcp/47101/p2p/self" || len(status.Peers) != 1 || status.ConsensusName != "tdpos" || status.ConsensusVersion != "1" {
+		t.Errorf("QueryNodeStatus node assert failed: %+v", status)
+	}
+	if len(status.PartialErrs) != 0 {
+		t.Error("QueryNodeStatus partial errors assert failed", status.PartialErrs)
+	}
+
+	// other queries failing is partial.
+	xc = &XClient{xc: &mockStatusXClient{MockXClient: &MockXClient{}, height: 10, noConsensus: true}}
+	status, err = xc.QueryNodeStatus()
+	if err != nil || status.TipHeight != 10 || status.ConsensusName != "" || len(status.PartialErrs) != 1 {
+		t.Error("QueryNodeStatus partial assert failed", status, err)
+	}
+
+	xc = &XClient{xc: &mockStatusXClient{MockXClient: &MockXClient{}, down: true}}
+	if _, err := xc.QueryNodeStatus(); err == nil {
+		t.Error("QueryNodeStatus unreachable assert failed")
+	}
+}
+
+func TestHealth(t *testing.T) {
+	newNode := func(height int64, down bool) *XClient {
+		return &XClient{xc: &mockStatusXClient{MockXClient: &MockXClient{}, height: height, down: down}}
+	}
+	nodes := []*XClient{newNode(100, false), newNode(98, false), newNode(90, false), newNode(0, true)}
+	// reachable without consensus status.
+	nodes[1].xc.(*mockStatusXClient).noConsensus = true
+
+	results := append(CheckHealth(nodes, 3), nodes[2].Health(nodes[:2], 10))
+	cases := []struct {
+		state HealthState
+		lag   int64
+	}{
+		{HealthSynced, 0},
+		{HealthSynced, 2},
+		{HealthLagging, 10},
+		{HealthUnreachable, 0},
+		// the same node is synced with a larger max lag.
+		{HealthSynced, 10},
+	}
+	for i, c := range cases {
+		if results[i].State != c.state || results[i].Lag != c.lag {
+			t.Errorf("Health case %d assert failed: %s %d", i, results[i].State, results[i].Lag)
+		}
+	}
+	if results[3].Err == nil || results[3].Status != nil {
+		t.Error("Health unreachable assert failed")
+	}
+}
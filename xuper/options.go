@@ -1,11 +1,18 @@
 package xuper
 
-import "github.com/pkg/errors"
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/superconsensus/matrix-sdk-go/v2/account"
+)
 
 type clientOptions struct {
-	configFile  string
-	useGrpcGZIP bool
-	grpcTLS     *grpcTLSConfig
+	configFile       string
+	useGrpcGZIP      bool
+	grpcTLS          *grpcTLSConfig
+	authRequireCheck bool
 }
 
 type grpcTLSConfig struct {
@@ -23,10 +30,14 @@ type requestOptions struct {
 	desc                 string
 	otherAuthRequire     []string
 	notPost              bool
+	feePayer             *account.Account
 }
 
 type queryOption struct {
-	bcname string
+	bcname       string
+	utxoCount    int64
+	workers      int
+	pollInterval time.Duration
 }
 
 // RequestOption tx opt.
@@ -46,6 +57,39 @@ func WithQueryBcname(bcname string) QueryOption {
 	}
 }
 
+// WithQueryUTXOCount max UTXOs listed per open, locked and frozen record by ListUTXOs, default 100.
+func WithQueryUTXOCount(count int64) QueryOption {
+	return func(opts *queryOption) error {
+		if count <= 0 {
+			return errors.New("invalid UTXO count")
+		}
+		opts.utxoCount = count
+		return nil
+	}
+}
+
+// WithQueryWorkers max concurrent RPCs of batch queries such as QueryBalances, default 8.
+func WithQueryWorkers(n int) QueryOption {
+	return func(opts *queryOption) error {
+		if n <= 0 {
+			return errors.New("invalid query workers")
+		}
+		opts.workers = n
+		return nil
+	}
+}
+
+// WithQueryPollInterval interval of chain status polling by WaitForHeight and Blocks, default 1 second.
+func WithQueryPollInterval(interval time.Duration) QueryOption {
+	return func(opts *queryOption) error {
+		if interval <= 0 {
+			return errors.New("invalid poll interval")
+		}
+		opts.pollInterval = interval
+		return nil
+	}
+}
+
 // WithConfigFile set xuperclient config file.
 func WithConfigFile(configFile string) ClientOption {
 	return func(opts *clientOptions) error {
@@ -62,6 +106,15 @@ func WithGrpcGZIP() ClientOption {
 	}
 }
 
+// WithAuthRequireCheck run CheckAuthRequire before PostTx and fail on missing signatures,
+// it costs ACL queries for every transaction posted.
+func WithAuthRequireCheck() ClientOption {
+	return func(opts *clientOptions) error {
+		opts.authRequireCheck = true
+		return nil
+	}
+}
+
 // WithGrpcTLS grpc TLS cert config.
 func WithGrpcTLS(serverName, cacertFile, certFile, keyFile string) ClientOption {
 	return func(opts *clientOptions) error {
@@ -84,6 +137,21 @@ func WithFeeFromAccount() RequestOption {
 	}
 }
 
+// WithFeePayer fee & gas paid by another account, such as a platform sponsoring its users.
+// The payer's contract account pays if it is set, otherwise the payer AK pays, the payer is added to AuthRequire
+// and signs the tx right after the initiator unless it is watch only. The signature of a watch only payer is added by
+// Transaction.AddAuthRequireSignature, or Transaction.SignAuthRequire where its key is, after the initiator and
+// before other AuthRequires.
+func WithFeePayer(payer *account.Account) RequestOption {
+	return func(opts *requestOptions) error {
+		if payer == nil {
+			return errors.New("fee payer can not be nil")
+		}
+		opts.feePayer = payer
+		return nil
+	}
+}
+
 // WithFee set fee.
 func WithFee(fee string) RequestOption {
 	return func(opts *requestOptions) error {
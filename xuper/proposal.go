@@ -84,6 +84,9 @@ func (p *Proposal) PreExecWithSelectUtxo() error {
 	preExecWithSelectUTXOResponse := new(pb.PreExecWithSelectUTXOResponse)
 
 	if p.cfg.ComplianceCheck.IsNeedComplianceCheck {
+		if p.request.opt.feePayer != nil {
+			return errors.New("fee payer is not supported with compliance check")
+		}
 		requestData, err := json.Marshal(req)
 		if err != nil {
 			return err
@@ -111,19 +114,24 @@ func (p *Proposal) PreExecWithSelectUtxo() error {
 			return errors.Wrap(err, "PreExecWithSelectUTXO failed")
 		}
 
-		// AK 发起交易，仅使用合约账户支付手续费时，需要选择 utxo。
-		if p.request.opt.onlyFeeFromAccount {
-			amount, ok := big.NewInt(0).SetString(p.request.opt.fee, 10)
-			if !ok {
-				return errors.Wrap(common.ErrInvalidAmount, "invalid request fee")
+		// AK 发起交易，仅使用合约账户支付手续费或者由其他账户代付手续费时，需要选择 utxo。
+		if feePayer := p.feePayerAddress(); feePayer != "" {
+			amount := big.NewInt(0)
+			if p.request.opt.fee != "" {
+				if _, ok := amount.SetString(p.request.opt.fee, 10); !ok {
+					return errors.Wrap(common.ErrInvalidAmount, "invalid request fee")
+				}
 			}
 			amount.Add(amount, big.NewInt(preExecWithSelectUTXOResponse.GetResponse().GetGasUsed()))
 
-			feeReq := p.genSelectUtxoRequest(p.request.initiatorAccount.GetContractAccount(), amount.String())
+			// no fee & gas, nothing to select.
+			if amount.Sign() > 0 {
+				feeReq := p.genSelectUtxoRequest(feePayer, amount.String())
 
-			p.feePreResp, err = c.SelectUTXO(ctx, feeReq)
-			if err != nil {
-				return errors.Wrap(err, "SelectUTXO from contract account failed")
+				p.feePreResp, err = c.SelectUTXO(ctx, feeReq)
+				if err != nil {
+					return errors.Wrapf(err, "SelectUTXO from fee payer %s failed", feePayer)
+				}
 			}
 		}
 	}
@@ -180,12 +188,13 @@ func (p *Proposal) GenCompleteTx() (*Transaction, error) {
 	}
 
 	transaction := &Transaction{
-		Tx:               tx,
-		ContractResponse: ContractResponse,
-		Bcname:           p.getChainName(),
-		Fee:              p.request.opt.fee,
-		GasUsed:          preResp.GetResponse().GetGasUsed(),
-		DigestHash:       digestHash,
+		Tx:                tx,
+		ContractResponse:  ContractResponse,
+		ContractResponses: p.invokeResponses(),
+		Bcname:            p.getChainName(),
+		Fee:               p.request.opt.fee,
+		GasUsed:           preResp.GetResponse().GetGasUsed(),
+		DigestHash:        digestHash,
 	}
 
 	return transaction, nil
@@ -198,6 +207,10 @@ func (p *Proposal) genTxWithComplianceCheck() (*pb.Transaction, error) {
 	)
 
 	if p.cfg.ComplianceCheck.IsNeedComplianceCheckFee {
+		// compliance check fee tx must be signed before the endorser checks it.
+		if p.request.initiatorAccount.IsWatchOnly() {
+			return nil, errors.Wrap(common.ErrWatchOnlyAccount, "compliance check fee tx can not be signed")
+		}
 		complianceCheckTx, err = p.genComplianceCheckTx()
 		if err != nil {
 			return nil, err
@@ -292,33 +305,54 @@ func (p *Proposal) genComplianceCheckTx() (*pb.Transaction, error) {
 func (p *Proposal) signTx(tx *pb.Transaction) ([]byte, error) {
 	initiator := p.request.initiatorAccount
 
-	cryptoClient := crypto.GetCryptoClient()
-	privateKey, err := cryptoClient.GetEcdsaPrivateKeyFromJsonStr(initiator.PrivateKey)
-	if err != nil {
-		return nil, err
-	}
-
 	digestHash, err := common.MakeTxDigestHash(tx)
 	if err != nil {
 		return nil, err
 	}
 
-	sign, err := cryptoClient.SignECDSA(privateKey, digestHash)
+	cryptoClient := crypto.GetCryptoClient()
 
-	signatureInfo := &pb.SignatureInfo{
-		PublicKey: initiator.PublicKey,
-		Sign:      sign,
-	}
+	// watch only initiator, the signature will be added by Transaction.AddInitiatorSignature.
+	if !initiator.IsWatchOnly() {
+		privateKey, err := cryptoClient.GetEcdsaPrivateKeyFromJsonStr(initiator.PrivateKey)
+		if err != nil {
+			return nil, err
+		}
+
+		sign, err := cryptoClient.SignECDSA(privateKey, digestHash)
 
-	var signatureInfos []*pb.SignatureInfo
-	signatureInfos = append(signatureInfos, signatureInfo)
+		signatureInfo := &pb.SignatureInfo{
+			PublicKey: initiator.PublicKey,
+			Sign:      sign,
+		}
 
-	tx.InitiatorSigns = signatureInfos
+		var signatureInfos []*pb.SignatureInfo
+		signatureInfos = append(signatureInfos, signatureInfo)
 
-	if len(tx.GetAuthRequireSigns()) == 0 {
-		tx.AuthRequireSigns = signatureInfos
-	} else {
-		tx.AuthRequireSigns = append(tx.AuthRequireSigns, signatureInfos...)
+		tx.InitiatorSigns = signatureInfos
+
+		if len(tx.GetAuthRequireSigns()) == 0 {
+			tx.AuthRequireSigns = signatureInfos
+		} else {
+			tx.AuthRequireSigns = append(tx.AuthRequireSigns, signatureInfos...)
+		}
+	}
+
+	// fee payer signs after the initiator, the same as the order of AuthRequire,
+	// AddInitiatorSignature puts the signature of a watch only initiator before it.
+	if payer := p.request.opt.feePayer; len(p.feePayerAuthRequire()) > 0 && !payer.IsWatchOnly() {
+		payerKey, err := cryptoClient.GetEcdsaPrivateKeyFromJsonStr(payer.PrivateKey)
+		if err != nil {
+			return nil, err
+		}
+		payerSign, err := cryptoClient.SignECDSA(payerKey, digestHash)
+		if err != nil {
+			return nil, errors.Wrap(err, "fee payer sign failed")
+		}
+		tx.AuthRequireSigns = append(tx.AuthRequireSigns, &pb.SignatureInfo{
+			PublicKey: payer.PublicKey,
+			Sign:      payerSign,
+		})
 	}
 
 	// make txid
@@ -401,12 +435,14 @@ func (p *Proposal) calcSelfAmount(totalSelected *big.Int) (string, error) {
 	preResp := p.preResp
 
 	// amount
-	if p.request.opt.contractInvokeAmount != "" {
-		invokeAmount, ok := big.NewInt(0).SetString(p.request.opt.contractInvokeAmount, 10)
-		if !ok {
-			return "", common.ErrInvalidAmount
+	for _, invoke := range p.request.allInvokes() {
+		if invoke.opt.contractInvokeAmount != "" {
+			invokeAmount, ok := big.NewInt(0).SetString(invoke.opt.contractInvokeAmount, 10)
+			if !ok {
+				return "", common.ErrInvalidAmount
+			}
+			amount.Add(amount, invokeAmount)
 		}
-		amount.Add(amount, invokeAmount)
 	}
 
 	if p.request.transferAmount != "" {
@@ -418,7 +454,7 @@ func (p *Proposal) calcSelfAmount(totalSelected *big.Int) (string, error) {
 	}
 
 	// fee
-	if !p.request.opt.onlyFeeFromAccount {
+	if p.feePayerAddress() == "" {
 		if p.request.opt.fee != "" {
 			fee, ok := big.NewInt(0).SetString(p.request.opt.fee, 10)
 			if !ok {
@@ -500,6 +536,7 @@ func (p *Proposal) genTx() (*pb.Transaction, error) {
 	}
 
 	authRequire = append(authRequire, p.request.initiatorAccount.GetAuthRequire())
+	authRequire = append(authRequire, p.feePayerAuthRequire()...)
 
 	if len(p.request.opt.otherAuthRequire) > 0 {
 		authRequire = append(authRequire, p.request.opt.otherAuthRequire...)
@@ -592,12 +629,14 @@ func (p *Proposal) generateMultiTxOutputs(selfAmount string, gasUsed *big.Int) (
 	}
 
 	// 2. transfer to contract
-	if req.opt.contractInvokeAmount != "" {
-		txOutput, err := p.makeTxOutput(req.contractName, req.opt.contractInvokeAmount)
-		if err != nil {
-			return nil, err
+	for _, invoke := range req.allInvokes() {
+		if invoke.opt.contractInvokeAmount != "" {
+			txOutput, err := p.makeTxOutput(invoke.contractName, invoke.opt.contractInvokeAmount)
+			if err != nil {
+				return nil, err
+			}
+			txOutputs = append(txOutputs, txOutput)
 		}
-		txOutputs = append(txOutputs, txOutput)
 	}
 
 	// 3. self
@@ -626,11 +665,6 @@ func (p *Proposal) makeFeeTxOutput() ([]*pb.TxOutput, error) {
 		return nil, err
 	}
 
-	// no gasUsed & fee.
-	if fee.Cmp(big.NewInt(0)) <= 0 {
-		return txOutputs, nil
-	}
-
 	if fee.Cmp(big.NewInt(0)) > 0 {
 		txOutput, err := p.makeTxOutput("$", fee.String())
 		if err != nil {
@@ -639,15 +673,15 @@ func (p *Proposal) makeFeeTxOutput() ([]*pb.TxOutput, error) {
 		txOutputs = append(txOutputs, txOutput)
 	}
 
-	// fee from contract account, calc account self output.
-	if p.request.opt.onlyFeeFromAccount && p.feePreResp != nil {
+	// fee from contract account or fee payer, calc payer self output.
+	if feePayer := p.feePayerAddress(); feePayer != "" && p.feePreResp != nil {
 		total, ok := big.NewInt(0).SetString(p.feePreResp.GetTotalSelected(), 10)
 		if !ok {
 			return nil, errors.New("invalid proposal feePreResp totalSelected")
 		}
 		feeSelf := total.Sub(total, fee)
 
-		txOutput, err := p.makeTxOutput(p.request.initiatorAccount.GetContractAccount(), feeSelf.String())
+		txOutput, err := p.makeTxOutput(feePayer, feeSelf.String())
 		if err != nil {
 			return nil, err
 		}
@@ -708,25 +742,59 @@ func (p *Proposal) getInitiator() string {
 	return initiator
 }
 
-func (p *Proposal) genInvokeRequests() ([]*pb.InvokeRequest, error) {
-	r := p.request
-	if r.contractName == "" && r.opt.contractInvokeAmount != "" {
-		return nil, errors.New("can not set contract invoke amount")
+// feePayerAddress address paying fee & gas other than the initiator, empty if the initiator pays.
+func (p *Proposal) feePayerAddress() string {
+	if p.request.opt.onlyFeeFromAccount {
+		return p.request.initiatorAccount.GetContractAccount()
 	}
+	if payer := p.request.opt.feePayer; payer != nil {
+		if payer.HasContractAccount() {
+			return payer.GetContractAccount()
+		}
+		return payer.Address
+	}
+	return ""
+}
 
-	if r.module == "" {
-		return nil, nil
+// feePayerAuthRequire AuthRequire of the fee payer, empty if no fee payer or it is the same as the initiator.
+func (p *Proposal) feePayerAuthRequire() []string {
+	payer := p.request.opt.feePayer
+	if payer == nil || payer.GetAuthRequire() == p.request.initiatorAccount.GetAuthRequire() {
+		return nil
 	}
+	return []string{payer.GetAuthRequire()}
+}
+
+func (p *Proposal) genInvokeRequests() ([]*pb.InvokeRequest, error) {
+	var invokeReqs []*pb.InvokeRequest
+	for _, r := range p.request.allInvokes() {
+		if r.contractName == "" && r.opt.contractInvokeAmount != "" {
+			return nil, errors.New("can not set contract invoke amount")
+		}
+
+		if r.module == "" {
+			continue
+		}
 
-	invokeReq := &pb.InvokeRequest{
-		ModuleName:   r.module,
-		ContractName: r.contractName,
-		MethodName:   r.methodName,
-		Args:         r.args,
-		Amount:       r.opt.contractInvokeAmount,
+		invokeReqs = append(invokeReqs, &pb.InvokeRequest{
+			ModuleName:   r.module,
+			ContractName: r.contractName,
+			MethodName:   r.methodName,
+			Args:         r.args,
+			Amount:       r.opt.contractInvokeAmount,
+		})
 	}
 
-	return []*pb.InvokeRequest{invokeReq}, nil
+	return invokeReqs, nil
+}
+
+// invokeResponses responses of the request's invokes, reserved contract and endorser responses come before them.
+func (p *Proposal) invokeResponses() []*pb.ContractResponse {
+	responses := p.preResp.GetResponse().GetResponses()
+	if n := p.request.invokeCount(); n < len(responses) {
+		return responses[len(responses)-n:]
+	}
+	return responses
 }
 
 func (p *Proposal) genInvokeRPCRequest() (*pb.InvokeRPCRequest, error) {
@@ -741,6 +809,7 @@ func (p *Proposal) genInvokeRPCRequest() (*pb.InvokeRPCRequest, error) {
 	}
 
 	authRequires = append(authRequires, p.request.initiatorAccount.GetAuthRequire())
+	authRequires = append(authRequires, p.feePayerAuthRequire()...)
 
 	if len(p.request.opt.otherAuthRequire) > 0 {
 		authRequires = append(authRequires, p.request.opt.otherAuthRequire...)
@@ -767,7 +836,7 @@ func (p *Proposal) calcTotalAmount() (int64, error) {
 		}
 	}
 
-	if !req.opt.onlyFeeFromAccount && req.opt.fee != "" {
+	if p.feePayerAddress() == "" && req.opt.fee != "" {
 		if amount, err := strconv.ParseInt(req.opt.fee, 10, 64); err == nil {
 			totalAmount += amount
 		} else {
@@ -775,11 +844,13 @@ func (p *Proposal) calcTotalAmount() (int64, error) {
 		}
 	}
 
-	if req.opt.contractInvokeAmount != "" {
-		if amount, err := strconv.ParseInt(req.opt.contractInvokeAmount, 10, 64); err == nil {
-			totalAmount += amount
-		} else {
-			return 0, err
+	for _, invoke := range req.allInvokes() {
+		if invoke.opt.contractInvokeAmount != "" {
+			if amount, err := strconv.ParseInt(invoke.opt.contractInvokeAmount, 10, 64); err == nil {
+				totalAmount += amount
+			} else {
+				return 0, err
+			}
 		}
 	}
 
@@ -10,6 +10,7 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/superconsensus/matrix-sdk-go/v2/account"
+	"github.com/superconsensus/matrix-sdk-go/v2/common"
 	"github.com/superconsensus/matrix-sdk-go/v2/common/config"
 	"github.com/xuperchain/xuperchain/service/pb"
 	"google.golang.org/grpc"
@@ -27,13 +28,34 @@ func TestBuild(t *testing.T) {
 	}
 
 	acc, _ := account.CreateAccount(1, 1)
-	a, e := xc.Transfer(acc, "a", "10")
+	to, _ := account.CreateAccount(1, 1)
+	a, e := xc.Transfer(acc, to.Address, "10")
 	if e != nil {
 		t.Error(e)
 	} else {
 		t.Log(a)
 	}
 }
+func TestTransferInvalidAddress(t *testing.T) {
+	xc := &XClient{
+		xc: &MockXClient{},
+		cfg: &config.CommConfig{
+			ComplianceCheck: config.ComplianceCheckConfig{},
+		},
+	}
+
+	acc, _ := account.CreateAccount(1, 1)
+	typo := acc.Address[:len(acc.Address)-1] + "1"
+	if typo == acc.Address {
+		typo = acc.Address[:len(acc.Address)-1] + "2"
+	}
+	for _, to := range []string{"", "a", typo} {
+		if _, err := xc.Transfer(acc, to, "10"); !errors.Is(err, common.ErrInvalidAddress) {
+			t.Errorf("Transfer to %s expect invalid address, got %v", to, err)
+		}
+	}
+}
+
 func TestNewProposal(t *testing.T) {
 	type Case struct {
 		xclient        *XClient
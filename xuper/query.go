@@ -3,10 +3,10 @@ package xuper
 import (
 	"context"
 	"encoding/hex"
-	"errors"
 	"fmt"
 	"math/big"
 
+	"github.com/pkg/errors"
 	"github.com/superconsensus/matrix-sdk-go/v2/common"
 	"github.com/xuperchain/xuperchain/service/pb"
 )
@@ -117,6 +117,25 @@ func (x *XClient) queryBlockByHeight(height int64, opts ...QueryOption) (*pb.Blo
 }
 
 func (x *XClient) queryAccountACL(account string, opts ...QueryOption) (*ACL, error) {
+	pbACL, err := x.queryAccountPBACL(account, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	acl := &ACL{}
+	pm := PermissionModel{}
+	pm.Rule = int32(pbACL.GetPm().GetRule())
+	pm.AcceptValue = pbACL.GetPm().GetAcceptValue()
+
+	acl.PM = pm
+	acl.AksWeight = pbACL.GetAksWeight()
+	acl.AkSets = newAKSetsFromPB(pbACL.GetAkSets())
+	return acl, nil
+
+}
+
+// queryAccountPBACL returns nil without error if the account not exists, the node returns status without ACL.
+func (x *XClient) queryAccountPBACL(account string, opts ...QueryOption) (*pb.Acl, error) {
 	opt, err := initQueryOpts(opts...)
 	if err != nil {
 		return nil, err
@@ -134,16 +153,7 @@ func (x *XClient) queryAccountACL(account string, opts ...QueryOption) (*ACL, er
 	if aclStatus.GetHeader().GetError() != pb.XChainErrorEnum_SUCCESS {
 		return nil, errors.New(aclStatus.GetHeader().GetError().String())
 	}
-
-	acl := &ACL{}
-	pm := PermissionModel{}
-	pm.Rule = int32(aclStatus.GetAcl().GetPm().GetRule())
-	pm.AcceptValue = aclStatus.GetAcl().GetPm().GetAcceptValue()
-
-	acl.PM = pm
-	acl.AksWeight = aclStatus.GetAcl().GetAksWeight()
-	return acl, nil
-
+	return aclStatus.GetAcl(), nil
 }
 
 func (x *XClient) queryMethodACL(name, method string, opts ...QueryOption) (*ACL, error) { // todo
@@ -178,6 +188,7 @@ func (x *XClient) queryMethodACL(name, method string, opts ...QueryOption) (*ACL
 
 	acl.PM = pm
 	acl.AksWeight = aclStatus.GetAcl().GetAksWeight()
+	acl.AkSets = newAKSetsFromPB(aclStatus.GetAcl().GetAkSets())
 	return acl, nil
 }
 
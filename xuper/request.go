@@ -3,6 +3,7 @@ package xuper
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/pkg/errors"
@@ -28,6 +29,9 @@ type Request struct {
 	transferTo     string
 	transferAmount string
 
+	// invokes contract invokes added by AddInvoke, executed after the request's own invoke.
+	invokes []*Request
+
 	opt *requestOptions
 }
 
@@ -114,6 +118,10 @@ func NewRequest(
 			"initiator contract account can not be nil when set fee from account.")
 	}
 
+	if opt.onlyFeeFromAccount && opt.feePayer != nil {
+		return nil, errors.Wrap(common.ErrInvalidParam, "can not set both fee from account and fee payer")
+	}
+
 	return &Request{
 		initiatorAccount: initiator,
 		module:           module,
@@ -162,14 +170,75 @@ func (r *Request) SetTransferAmount(amount string) error {
 	return nil
 }
 
+// AddInvoke append the contract invoke of other to the request, all invokes are executed in order in one transaction.
+// other is built by NewInvokeContractRequest, NewDeployContractRequest and so on, WithContractInvokeAmount of other
+// is the amount transferred to its contract, other options of other are ignored.
+func (r *Request) AddInvoke(other *Request) error {
+	if other == nil || other == r {
+		return common.ErrInvalidParam
+	}
+	for _, invoke := range other.allInvokes() {
+		if invoke.module == "" {
+			continue
+		}
+		if invoke.transferTo != "" {
+			return errors.Wrap(common.ErrInvalidParam, "invoke can not transfer")
+		}
+		r.invokes = append(r.invokes, invoke)
+	}
+	return nil
+}
+
+// allInvokes the request itself and the invokes added by AddInvoke.
+func (r *Request) allInvokes() []*Request {
+	return append([]*Request{r}, r.invokes...)
+}
+
+// invokeCount count of contract invokes of the transaction.
+func (r *Request) invokeCount() int {
+	count := 0
+	for _, invoke := range r.allInvokes() {
+		if invoke.module != "" {
+			count++
+		}
+	}
+	return count
+}
+
+// NewMultiInvokeRequest new request executes several contract invokes in one transaction, such as approve and transfer,
+// or deploy and init call.
+//
+// Parameters:
+//   - `from`   : Initiator of the transaction.
+//   - `invokes`: Built by NewInvokeContractRequest, NewDeployContractRequest and so on, see Request.AddInvoke.
+//   - `opts`   : Options of the transaction, such as WithFee.
+func NewMultiInvokeRequest(from *account.Account, invokes []*Request, opts ...RequestOption) (*Request, error) {
+	if len(invokes) == 0 {
+		return nil, common.ErrInvalidParam
+	}
+	req, err := NewRequest(from, "", "", "", nil, "", "", opts...)
+	if err != nil {
+		return nil, err
+	}
+	for _, invoke := range invokes {
+		if err := req.AddInvoke(invoke); err != nil {
+			return nil, err
+		}
+	}
+	if req.invokeCount() == 0 {
+		return nil, common.ErrInvalidParam
+	}
+	return req, nil
+}
+
 // NewTransferRequest set
 func NewTransferRequest(from *account.Account, to, amount string, opts ...RequestOption) (*Request, error) {
 	if from == nil {
 		return nil, common.ErrInvalidInitiator
 	}
 
-	if to == "" {
-		return nil, common.ErrInvalidParam
+	if err := account.ValidateAddress(to); err != nil {
+		return nil, err
 	}
 
 	amount, ok := common.IsValidAmount(amount)
@@ -244,6 +313,32 @@ func NewCreateContractAccountRequest(from *account.Account, contractAccount stri
 	return NewRequest(from, Xkernel3Module, "", XkernelNewAccountMethod, args, "", "", opts...)
 }
 
+// NewCreateContractAccountWithACLRequest new request for create contract account with ACL.
+func NewCreateContractAccountWithACLRequest(from *account.Account, contractAccount string, acl *ACL, opts ...RequestOption) (*Request, error) {
+	if from == nil || from.HasContractAccount() {
+		return nil, common.ErrInvalidAccount
+	}
+	if acl == nil {
+		return nil, common.ErrInvalidACL
+	}
+	if err := acl.Validate(); err != nil {
+		return nil, err
+	}
+
+	addr, err := account.ParseAddress(contractAccount)
+	if err != nil || !addr.IsContractAccount() {
+		return nil, common.ErrInvalidContractAccount
+	}
+	// xkernel NewAccount method accepts the 16 numbers only.
+	number := strings.TrimPrefix(strings.SplitN(contractAccount, "@", 2)[0], contractAccountPrefix)
+
+	args, err := genAccountACLArgs(acl, number)
+	if err != nil {
+		return nil, err
+	}
+	return NewRequest(from, Xkernel3Module, "", XkernelNewAccountMethod, args, "", "", opts...)
+}
+
 // NewSetMethodACLRequest new request for set method ACL.
 func NewSetMethodACLRequest(from *account.Account, name, method string, acl *ACL, opts ...RequestOption) (*Request, error) {
 	if from == nil {
@@ -251,7 +346,10 @@ func NewSetMethodACLRequest(from *account.Account, name, method string, acl *ACL
 	}
 
 	if acl == nil {
-		return nil, errors.New("invalid ACL")
+		return nil, common.ErrInvalidACL
+	}
+	if err := acl.Validate(); err != nil {
+		return nil, err
 	}
 
 	if method == "" || name == "" {
@@ -271,6 +369,13 @@ func NewSetAccountACLRequest(from *account.Account, acl *ACL, opts ...RequestOpt
 		return nil, common.ErrInvalidAccount
 	}
 
+	if acl == nil {
+		return nil, common.ErrInvalidACL
+	}
+	if err := acl.Validate(); err != nil {
+		return nil, err
+	}
+
 	args, err := genAccountACLArgs(acl, from.GetContractAccount())
 	if err != nil {
 		return nil, err
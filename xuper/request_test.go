@@ -0,0 +1,238 @@
+package xuper
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/xuperchain/xuperchain/service/pb"
+	"google.golang.org/grpc"
+
+	"github.com/superconsensus/matrix-sdk-go/v2/account"
+	"github.com/superconsensus/matrix-sdk-go/v2/common"
+	"github.com/superconsensus/matrix-sdk-go/v2/common/config"
+	"github.com/superconsensus/matrix-sdk-go/v2/crypto"
+)
+
+// mockMultiInvokeXClient mock node returns a reserved contract response then one response per invoke.
+type mockMultiInvokeXClient struct {
+	*MockXClient
+}
+
+func (m *mockMultiInvokeXClient) PreExecWithSelectUTXO(ctx context.Context, in *pb.PreExecWithSelectUTXORequest, opts ...grpc.CallOption) (*pb.PreExecWithSelectUTXOResponse, error) {
+	resp, err := m.MockXClient.PreExecWithSelectUTXO(ctx, in, opts...)
+	if err != nil {
+		return nil, err
+	}
+	resp.Response.Responses = []*pb.ContractResponse{{Status: 200, Body: []byte("reserved")}}
+	for _, req := range in.GetRequest().GetRequests() {
+		resp.Response.Responses = append(resp.Response.Responses, &pb.ContractResponse{Status: 200, Body: []byte(req.GetMethodName())})
+	}
+	return resp, nil
+}
+
+func TestNewMultiInvokeRequest(t *testing.T) {
+	xc := &XClient{xc: &mockMultiInvokeXClient{&MockXClient{}}, cfg: &config.CommConfig{TxVersion: 1}}
+	acc, _ := account.CreateAccount(1, 1)
+
+	approve, err := NewInvokeContractRequest(acc, WasmContractModule, "token", "approve", map[string]string{"to": "bob"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	transfer, err := NewInvokeContractRequest(acc, WasmContractModule, "market", "buy", nil, WithContractInvokeAmount("20"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewMultiInvokeRequest(acc, nil); err == nil {
+		t.Error("NewMultiInvokeRequest empty invokes assert failed")
+	}
+	selfTransfer, _ := NewTransferRequest(acc, acc.Address, "1")
+	if _, err := NewMultiInvokeRequest(acc, []*Request{selfTransfer}); err == nil {
+		t.Error("NewMultiInvokeRequest transfer only assert failed")
+	}
+	approve.transferTo = "bob"
+	if _, err := NewMultiInvokeRequest(acc, []*Request{approve}); err == nil {
+		t.Error("NewMultiInvokeRequest invoke with transfer assert failed")
+	}
+	approve.transferTo = ""
+
+	req, err := NewMultiInvokeRequest(acc, []*Request{approve, transfer}, WithNotPost())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err := xc.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reqs := tx.Tx.GetContractRequests()
+	if len(reqs) != 2 || reqs[0].GetMethodName() != "approve" || reqs[1].GetMethodName() != "buy" || reqs[1].GetAmount() != "20" {
+		t.Fatal("multi invoke contract requests assert failed", reqs)
+	}
+	if len(tx.ContractResponses) != 2 || string(tx.ContractResponses[0].Body) != "approve" || string(tx.ContractResponses[1].Body) != "buy" {
+		t.Error("multi invoke responses assert failed", tx.ContractResponses)
+	}
+	if string(tx.ContractResponse.Body) != "buy" {
+		t.Error("multi invoke last response assert failed", tx.ContractResponse)
+	}
+
+	var toMarket *big.Int
+	for _, output := range tx.Tx.GetTxOutputs() {
+		if string(output.GetToAddr()) == "market" {
+			toMarket = new(big.Int).SetBytes(output.GetAmount())
+		}
+	}
+	if toMarket == nil || toMarket.Int64() != 20 {
+		t.Error("multi invoke contract amount output assert failed", toMarket)
+	}
+}
+
+// mockSelectXClient mock node records the amount selected from each address.
+type mockSelectXClient struct {
+	*MockXClient
+	selected map[string]string
+	noGas    bool
+}
+
+func (m *mockSelectXClient) PreExecWithSelectUTXO(ctx context.Context, in *pb.PreExecWithSelectUTXORequest, opts ...grpc.CallOption) (*pb.PreExecWithSelectUTXOResponse, error) {
+	resp, err := m.MockXClient.PreExecWithSelectUTXO(ctx, in, opts...)
+	if err == nil && m.noGas {
+		resp.Response.GasUsed = 0
+	}
+	return resp, err
+}
+
+func (m *mockSelectXClient) SelectUTXO(ctx context.Context, in *pb.UtxoInput, opts ...grpc.CallOption) (*pb.UtxoOutput, error) {
+	m.selected[in.GetAddress()] = in.GetTotalNeed()
+	return m.MockXClient.SelectUTXO(ctx, in, opts...)
+}
+
+func TestWithFeePayer(t *testing.T) {
+	xc := &XClient{xc: &MockXClient{}, cfg: &config.CommConfig{TxVersion: 1}}
+	from, _ := account.CreateAccount(1, 1)
+	to, _ := account.CreateAccount(1, 1)
+	payer, _ := account.CreateAccount(1, 1)
+
+	if _, err := NewTransferRequest(from, to.Address, "10", WithFeePayer(nil)); err == nil {
+		t.Error("WithFeePayer nil assert failed")
+	}
+	from.SetContractAccount("XC1111111111111111@xuper")
+	if _, err := NewTransferRequest(from, to.Address, "10", WithFeePayer(payer), WithFeeFromAccount()); err == nil {
+		t.Error("WithFeePayer with fee from account assert failed")
+	}
+	from.RemoveContractAccount()
+
+	tx, err := xc.Transfer(from, to.Address, "10", WithFee("5"), WithFeePayer(payer), WithNotPost())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ar := tx.Tx.GetAuthRequire(); len(ar) != 2 || ar[0] != from.Address || ar[1] != payer.Address {
+		t.Error("WithFeePayer auth require assert failed", ar)
+	}
+
+	outputs := map[string]int64{}
+	for _, output := range tx.Tx.GetTxOutputs() {
+		outputs[string(output.GetToAddr())] += new(big.Int).SetBytes(output.GetAmount()).Int64()
+	}
+	// mock node selects need+100, gas is 10.
+	if outputs[to.Address] != 10 || outputs[from.Address] != 100 || outputs["$"] != 15 || outputs[payer.Address] != 100 {
+		t.Error("WithFeePayer outputs assert failed", outputs)
+	}
+	payerInputs := 0
+	for _, input := range tx.Tx.GetTxInputs() {
+		if string(input.GetFromAddr()) == payer.Address {
+			payerInputs++
+		}
+	}
+	if payerInputs == 0 {
+		t.Error("WithFeePayer payer inputs assert failed")
+	}
+
+	signs := tx.Tx.GetAuthRequireSigns()
+	if len(signs) != 2 || signs[1].GetPublicKey() != payer.PublicKey {
+		t.Fatal("WithFeePayer signs assert failed", len(signs))
+	}
+	cryptoClient := crypto.GetCryptoClient()
+	publicKey, _ := cryptoClient.GetEcdsaPublicKeyFromJsonStr(payer.PublicKey)
+	digest, _ := common.MakeTxDigestHash(tx.Tx)
+	if ok, err := cryptoClient.VerifyECDSA(publicKey, signs[1].GetSign(), digest); err != nil || !ok {
+		t.Error("WithFeePayer payer sign assert failed", err)
+	}
+
+	// sponsor pays gas only.
+	mock := &mockSelectXClient{MockXClient: &MockXClient{}, selected: map[string]string{}}
+	xc = &XClient{xc: mock, cfg: &config.CommConfig{TxVersion: 1}}
+	tx, err = xc.InvokeWasmContract(from, "counter", "increase", map[string]string{"key": "k"}, WithFeePayer(payer), WithNotPost())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mock.selected[payer.Address] != "10" {
+		t.Error("WithFeePayer gas only select assert failed", mock.selected)
+	}
+	if _, ok := mock.selected[from.Address]; ok {
+		t.Error("WithFeePayer gas only initiator select assert failed", mock.selected)
+	}
+
+	// no fee & gas, payer UTXOs are not consumed.
+	mock.selected = map[string]string{}
+	mock.noGas = true
+	tx, err = xc.Transfer(from, to.Address, "10", WithFeePayer(payer), WithNotPost())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := mock.selected[payer.Address]; ok {
+		t.Error("WithFeePayer zero fee select assert failed", mock.selected)
+	}
+	for _, input := range tx.Tx.GetTxInputs() {
+		if string(input.GetFromAddr()) == payer.Address {
+			t.Error("WithFeePayer zero fee payer inputs assert failed")
+		}
+	}
+}
+
+func TestWithFeePayerWatchOnlyInitiator(t *testing.T) {
+	xc := &XClient{xc: &MockXClient{}, cfg: &config.CommConfig{TxVersion: 1}}
+	from, _ := account.CreateAccount(1, 1)
+	to, _ := account.CreateAccount(1, 1)
+	payer, _ := account.CreateAccount(1, 1)
+	watchFrom, err := account.NewWatchOnlyAccount(from.Address, from.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the payer with key signs even though the initiator is watch only.
+	tx, err := xc.Transfer(watchFrom, to.Address, "10", WithFeePayer(payer), WithNotPost())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signs := tx.Tx.GetAuthRequireSigns(); len(signs) != 1 || signs[0].GetPublicKey() != payer.PublicKey {
+		t.Fatal("WithFeePayer watch only initiator payer sign assert failed", signs)
+	}
+
+	cryptoClient := crypto.GetCryptoClient()
+	privateKey, _ := cryptoClient.GetEcdsaPrivateKeyFromJsonStr(from.PrivateKey)
+	sign, _ := cryptoClient.SignECDSA(privateKey, tx.DigestHash)
+	if err := tx.AddInitiatorSignature(from.PublicKey, sign); err != nil {
+		t.Fatal(err)
+	}
+	signs := tx.Tx.GetAuthRequireSigns()
+	if len(signs) != 2 || signs[0].GetPublicKey() != from.PublicKey || signs[1].GetPublicKey() != payer.PublicKey {
+		t.Error("WithFeePayer watch only initiator sign order assert failed", signs)
+	}
+	if len(tx.Tx.GetInitiatorSigns()) != 1 {
+		t.Error("WithFeePayer watch only initiator signs assert failed", tx.Tx.GetInitiatorSigns())
+	}
+
+	// a watch only payer signs as AuthRequire only.
+	watchPayer, _ := account.NewWatchOnlyAccount(payer.Address, payer.PublicKey)
+	tx, err = xc.Transfer(from, to.Address, "10", WithFeePayer(watchPayer), WithNotPost())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.SignAuthRequire(payer); err != nil {
+		t.Fatal(err)
+	}
+	if len(tx.Tx.GetAuthRequireSigns()) != 2 || len(tx.Tx.GetInitiatorSigns()) != 1 {
+		t.Error("SignAuthRequire assert failed", len(tx.Tx.GetAuthRequireSigns()), len(tx.Tx.GetInitiatorSigns()))
+	}
+}
@@ -0,0 +1,191 @@
+package xuper
+
+import (
+	"bytes"
+	"encoding/hex"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/xuperchain/xuperchain/service/pb"
+)
+
+// StateOp kind of StateChange.
+type StateOp string
+
+// Ops of StateChange.
+const (
+	// StateOpCreate the key does not exist before.
+	StateOpCreate StateOp = "create"
+	// StateOpUpdate the key value is changed.
+	StateOpUpdate StateOp = "update"
+	// StateOpDelete the key is deleted.
+	StateOpDelete StateOp = "delete"
+)
+
+// stateDelFlag value written to a deleted key, the same as the chain ledger.
+var stateDelFlag = []byte("\x00")
+
+// SimulationResult full pre-exec result of a request, nothing is signed or posted.
+type SimulationResult struct {
+	// Responses every contract response in execution order, reserved contracts and endorser ones included,
+	// the last one is the response of the request.
+	Responses []*SimulatedResponse
+	GasUsed   int64
+	// Buckets read and write set per contract bucket.
+	Buckets map[string]*BucketRWSet
+	// Diff state keys the tx would change, ordered by bucket and key.
+	Diff []*StateChange
+
+	// Raw the original pre-exec response.
+	Raw *pb.PreExecWithSelectUTXOResponse
+}
+
+// SimulatedResponse contract response with its request.
+type SimulatedResponse struct {
+	// Request nil if the node returns more responses than requests.
+	Request *DecodedContractRequest
+	Status  int32
+	Message string
+	Body    []byte
+}
+
+// BucketRWSet keys read and written in a bucket.
+type BucketRWSet struct {
+	Reads  []*StateRead
+	Writes []*StateWrite
+}
+
+// StateRead key read by the tx, RefTxid and RefOffset locate the TxOutputExt which wrote the current value.
+type StateRead struct {
+	Key string
+	// RefTxid empty if the key does not exist.
+	RefTxid   string
+	RefOffset int32
+}
+
+// StateWrite key written by the tx.
+type StateWrite struct {
+	Key   string
+	Value []byte
+}
+
+// StateChange state key value before and after the tx.
+type StateChange struct {
+	Bucket string
+	Key    string
+	Op     StateOp
+	// Before nil for StateOpCreate.
+	Before []byte
+	// After nil for StateOpDelete.
+	After []byte
+}
+
+// SimulateTx pre-execute the request and return the full result for review before signing.
+func (x *XClient) SimulateTx(req *Request) (*SimulationResult, error) {
+	proposal, err := NewProposal(x, req, x.cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := proposal.PreExecWithSelectUtxo(); err != nil {
+		return nil, err
+	}
+
+	resp := proposal.preResp.GetResponse()
+	result := &SimulationResult{
+		GasUsed: resp.GetGasUsed(),
+		Buckets: map[string]*BucketRWSet{},
+		Raw:     proposal.preResp,
+	}
+	for i, cr := range resp.GetResponses() {
+		sr := &SimulatedResponse{
+			Status:  cr.GetStatus(),
+			Message: cr.GetMessage(),
+			Body:    cr.GetBody(),
+		}
+		if i < len(resp.GetRequests()) {
+			sr.Request = decodeContractRequest(resp.GetRequests()[i])
+		}
+		result.Responses = append(result.Responses, sr)
+	}
+
+	bucket := func(name string) *BucketRWSet {
+		if result.Buckets[name] == nil {
+			result.Buckets[name] = &BucketRWSet{}
+		}
+		return result.Buckets[name]
+	}
+	reads := map[string]*pb.TxInputExt{}
+	for _, input := range resp.GetInputs() {
+		bucket(input.GetBucket()).Reads = append(bucket(input.GetBucket()).Reads, &StateRead{
+			Key:       string(input.GetKey()),
+			RefTxid:   hex.EncodeToString(input.GetRefTxid()),
+			RefOffset: input.GetRefOffset(),
+		})
+		reads[input.GetBucket()+"/"+string(input.GetKey())] = input
+	}
+	for _, output := range resp.GetOutputs() {
+		bucket(output.GetBucket()).Writes = append(bucket(output.GetBucket()).Writes, &StateWrite{
+			Key:   string(output.GetKey()),
+			Value: output.GetValue(),
+		})
+	}
+
+	result.Diff, err = x.stateDiff(resp.GetOutputs(), reads, proposal.getChainName())
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// stateDiff the value before a write is the TxOutputExt referenced by the read of the same key.
+func (x *XClient) stateDiff(outputs []*pb.TxOutputExt, reads map[string]*pb.TxInputExt, bcname string) ([]*StateChange, error) {
+	refTxs := map[string]*pb.Transaction{}
+	diff := []*StateChange{}
+	for _, output := range outputs {
+		change := &StateChange{
+			Bucket: output.GetBucket(),
+			Key:    string(output.GetKey()),
+			After:  output.GetValue(),
+		}
+
+		input := reads[output.GetBucket()+"/"+string(output.GetKey())]
+		if len(input.GetRefTxid()) != 0 {
+			refTxid := hex.EncodeToString(input.GetRefTxid())
+			if refTxs[refTxid] == nil {
+				tx, err := x.queryTxByID(refTxid, WithQueryBcname(bcname))
+				if err != nil {
+					return nil, errors.Wrapf(err, "query value of %s/%s failed", change.Bucket, change.Key)
+				}
+				refTxs[refTxid] = tx
+			}
+			if offset := int(input.GetRefOffset()); offset < len(refTxs[refTxid].GetTxOutputsExt()) {
+				change.Before = refTxs[refTxid].GetTxOutputsExt()[offset].GetValue()
+			}
+		}
+		if bytes.Equal(change.Before, stateDelFlag) {
+			change.Before = nil
+		}
+
+		switch {
+		case bytes.Equal(change.After, stateDelFlag):
+			if change.Before == nil {
+				continue
+			}
+			change.Op, change.After = StateOpDelete, nil
+		case change.Before == nil:
+			change.Op = StateOpCreate
+		case bytes.Equal(change.Before, change.After):
+			continue
+		default:
+			change.Op = StateOpUpdate
+		}
+		diff = append(diff, change)
+	}
+	sort.SliceStable(diff, func(i, j int) bool {
+		if diff[i].Bucket != diff[j].Bucket {
+			return diff[i].Bucket < diff[j].Bucket
+		}
+		return diff[i].Key < diff[j].Key
+	})
+	return diff, nil
+}
@@ -0,0 +1,108 @@
+package xuper
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/xuperchain/xuperchain/service/pb"
+	"google.golang.org/grpc"
+
+	"github.com/superconsensus/matrix-sdk-go/v2/account"
+	"github.com/superconsensus/matrix-sdk-go/v2/common/config"
+)
+
+// mockSimXClient mock node pre-exec with a reserved contract, ref txs hold the current state values.
+type mockSimXClient struct {
+	*MockXClient
+	resp   *pb.InvokeResponse
+	refTxs map[string]*pb.Transaction
+}
+
+func (m *mockSimXClient) PreExecWithSelectUTXO(ctx context.Context, in *pb.PreExecWithSelectUTXORequest, opts ...grpc.CallOption) (*pb.PreExecWithSelectUTXOResponse, error) {
+	resp := *m.resp
+	resp.Requests = append([]*pb.InvokeRequest{{ModuleName: "wasm", ContractName: "identity", MethodName: "verify"}}, in.GetRequest().GetRequests()...)
+	return &pb.PreExecWithSelectUTXOResponse{Header: newHeader(), Bcname: in.GetBcname(), Response: &resp}, nil
+}
+
+func (m *mockSimXClient) QueryTx(ctx context.Context, in *pb.TxStatus, opts ...grpc.CallOption) (*pb.TxStatus, error) {
+	return &pb.TxStatus{Header: newHeader(), Tx: m.refTxs[hex.EncodeToString(in.GetTxid())]}, nil
+}
+
+func TestSimulateTx(t *testing.T) {
+	mock := &mockSimXClient{
+		MockXClient: &MockXClient{},
+		resp: &pb.InvokeResponse{
+			GasUsed: 42,
+			Responses: []*pb.ContractResponse{
+				{Status: 200, Body: []byte("identity ok")},
+				{Status: 200, Message: "ok", Body: []byte("2")},
+			},
+			Inputs: []*pb.TxInputExt{
+				{Bucket: "counter", Key: []byte("alice"), RefTxid: []byte{1}, RefOffset: 1},
+				{Bucket: "counter", Key: []byte("bob"), RefTxid: []byte{1}, RefOffset: 0},
+				{Bucket: "counter", Key: []byte("carol")},
+				{Bucket: "counter", Key: []byte("dave"), RefTxid: []byte{2}},
+				{Bucket: "identity", Key: []byte("alice"), RefTxid: []byte{2}},
+			},
+			Outputs: []*pb.TxOutputExt{
+				{Bucket: "counter", Key: []byte("alice"), Value: []byte("2")},
+				{Bucket: "counter", Key: []byte("bob"), Value: []byte("1")},
+				{Bucket: "counter", Key: []byte("carol"), Value: []byte("1")},
+				{Bucket: "counter", Key: []byte("dave"), Value: stateDelFlag},
+			},
+		},
+		refTxs: map[string]*pb.Transaction{
+			"01": {TxOutputsExt: []*pb.TxOutputExt{{Value: []byte("1")}, {Value: []byte("1")}}},
+			"02": {TxOutputsExt: []*pb.TxOutputExt{{Value: []byte("d")}}},
+		},
+	}
+	xc := &XClient{xc: mock, cfg: &config.CommConfig{TxVersion: 1}}
+	acc, _ := account.CreateAccount(1, 1)
+	req, err := NewInvokeContractRequest(acc, "wasm", "counter", "increase", map[string]string{"key": "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := xc.SimulateTx(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.GasUsed != 42 || len(result.Responses) != 2 {
+		t.Fatal("SimulateTx result assert failed", result.GasUsed, len(result.Responses))
+	}
+	if r := result.Responses[0]; r.Request.ContractName != "identity" || string(r.Body) != "identity ok" {
+		t.Errorf("SimulateTx reserved response assert failed: %+v", r)
+	}
+	if r := result.Responses[1]; r.Request.MethodName != "increase" || string(r.Request.Args["key"]) != "alice" || string(r.Body) != "2" {
+		t.Errorf("SimulateTx response assert failed: %+v", r)
+	}
+
+	counter, identity := result.Buckets["counter"], result.Buckets["identity"]
+	if len(counter.Reads) != 4 || len(counter.Writes) != 4 || len(identity.Reads) != 1 || len(identity.Writes) != 0 {
+		t.Fatal("SimulateTx buckets assert failed")
+	}
+	if r := counter.Reads[0]; r.Key != "alice" || r.RefTxid != "01" || r.RefOffset != 1 {
+		t.Errorf("SimulateTx read assert failed: %+v", r)
+	}
+
+	expect := []struct {
+		key    string
+		op     StateOp
+		before string
+		after  string
+	}{
+		{"alice", StateOpUpdate, "1", "2"},
+		{"carol", StateOpCreate, "", "1"},
+		{"dave", StateOpDelete, "d", ""},
+	}
+	if len(result.Diff) != len(expect) {
+		t.Fatal("SimulateTx diff count assert failed", len(result.Diff))
+	}
+	for i, e := range expect {
+		c := result.Diff[i]
+		if c.Bucket != "counter" || c.Key != e.key || c.Op != e.op || string(c.Before) != e.before || string(c.After) != e.after {
+			t.Errorf("SimulateTx diff %s assert failed: %+v", e.key, c)
+		}
+	}
+}
@@ -15,7 +15,9 @@ import (
 type Transaction struct {
 	Tx               *pb.Transaction
 	ContractResponse *pb.ContractResponse
-	Bcname           string
+	// ContractResponses responses of the request's invokes in order, see Request.AddInvoke.
+	ContractResponses []*pb.ContractResponse
+	Bcname            string
 
 	Fee     string
 	GasUsed int64
@@ -25,45 +27,191 @@ type Transaction struct {
 
 // Sign account sign for tx, for multisign.multisign
 func (t *Transaction) Sign(account *account.Account) error {
+	signatureInfo, err := t.sign(account)
+	if err != nil {
+		return err
+	}
+
+	t.Tx.AuthRequireSigns = append(t.Tx.AuthRequireSigns, signatureInfo)
+	t.Tx.InitiatorSigns = append(t.Tx.InitiatorSigns, signatureInfo)
+
+	// make txid
+	t.Tx.Txid, err = common.MakeTransactionID(t.Tx)
+
+	return err
+}
+
+// SignAuthRequire account sign for tx as one of AuthRequire only, such as the fee payer,
+// the signature is not added to InitiatorSigns. Signatures must be added in the order of AuthRequire.
+func (t *Transaction) SignAuthRequire(account *account.Account) error {
+	signatureInfo, err := t.sign(account)
+	if err != nil {
+		return err
+	}
+
+	t.Tx.AuthRequireSigns = append(t.Tx.AuthRequireSigns, signatureInfo)
+
+	t.Tx.Txid, err = common.MakeTransactionID(t.Tx)
+	return err
+}
+
+func (t *Transaction) sign(account *account.Account) (*pb.SignatureInfo, error) {
 	if account == nil {
-		return errors.New("Transaction sign account can not be nil")
+		return nil, errors.New("Transaction sign account can not be nil")
+	}
+	if account.IsWatchOnly() {
+		return nil, common.ErrWatchOnlyAccount
 	}
 	// 对于多签，在交易预执行时就需要写好所有的需要签名的地址到 AuthRequire 字段，其他地址再进行签名时，需要检查是否已经在 AuthRequire 字段中。
 	// 同时签名的顺序也要保持一致，不然上链时会失败。
 	if !inSlice(t.Tx.AuthRequire, account.GetAuthRequire()) {
-		return errors.New("this account not in transaction's AuthRequire list")
+		return nil, errors.New("this account not in transaction's AuthRequire list")
 	}
 
-	if t.DigestHash == nil {
-		digestHash, err := common.MakeTxDigestHash(t.Tx)
-		if err != nil {
-			return err
-		}
-		t.DigestHash = digestHash
+	if err := t.makeDigestHash(); err != nil {
+		return nil, err
 	}
 
 	cryptoClient := crypto.GetCryptoClient()
 	privateKey, err := cryptoClient.GetEcdsaPrivateKeyFromJsonStr(account.PrivateKey)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	sign, err := cryptoClient.SignECDSA(privateKey, t.DigestHash)
+	if err != nil {
+		return nil, err
+	}
 
-	signatureInfo := &pb.SignatureInfo{
+	return &pb.SignatureInfo{
 		PublicKey: account.PublicKey,
 		Sign:      sign,
+	}, nil
+}
+
+// AddInitiatorSignature add signature of DigestHash produced by HSM or other service for watch only initiator.
+// It is put at the position of the initiator in AuthRequire, so a fee payer signed with the tx keeps its order,
+// other AuthRequire signatures must be added after it.
+//
+// Parameters:
+//   - `publicKey`: JSON encoded public key of the initiator.
+//   - `sign`     : Signature of DigestHash.
+func (t *Transaction) AddInitiatorSignature(publicKey string, sign []byte) error {
+	if len(t.Tx.InitiatorSigns) > 0 {
+		return errors.New("transaction already has initiator signature")
+	}
+
+	signatureInfo, err := t.verifySignature(publicKey, sign)
+	if err != nil {
+		return err
+	}
+
+	t.Tx.InitiatorSigns = []*pb.SignatureInfo{signatureInfo}
+
+	// signatures before the initiator's in AuthRequire, at most those already added.
+	index := len(t.Tx.AuthRequireSigns)
+	if i := authRequireIndex(t.Tx.AuthRequire, t.Tx.Initiator); i >= 0 && i < index {
+		index = i
+	}
+	signs := append([]*pb.SignatureInfo{}, t.Tx.AuthRequireSigns[:index]...)
+	signs = append(signs, signatureInfo)
+	t.Tx.AuthRequireSigns = append(signs, t.Tx.AuthRequireSigns[index:]...)
+
+	t.Tx.Txid, err = common.MakeTransactionID(t.Tx)
+	return err
+}
+
+// AddSignature add signature of DigestHash produced by HSM or other service, for multisign.
+// It is the same as Sign but the signature is produced externally.
+//
+// Parameters:
+//   - `publicKey`: JSON encoded public key of the signer, signer address must be in AuthRequire.
+//   - `sign`     : Signature of DigestHash.
+func (t *Transaction) AddSignature(publicKey string, sign []byte) error {
+	signatureInfo, err := t.verifySignature(publicKey, sign)
+	if err != nil {
+		return err
 	}
 
 	t.Tx.AuthRequireSigns = append(t.Tx.AuthRequireSigns, signatureInfo)
 	t.Tx.InitiatorSigns = append(t.Tx.InitiatorSigns, signatureInfo)
 
-	// make txid
 	t.Tx.Txid, err = common.MakeTransactionID(t.Tx)
+	return err
+}
+
+// AddAuthRequireSignature add signature of DigestHash produced by HSM or other service, such as the fee payer.
+// It is the same as SignAuthRequire but the signature is produced externally.
+//
+// Parameters:
+//   - `publicKey`: JSON encoded public key of the signer, signer address must be in AuthRequire.
+//   - `sign`     : Signature of DigestHash.
+func (t *Transaction) AddAuthRequireSignature(publicKey string, sign []byte) error {
+	signatureInfo, err := t.verifySignature(publicKey, sign)
+	if err != nil {
+		return err
+	}
 
+	t.Tx.AuthRequireSigns = append(t.Tx.AuthRequireSigns, signatureInfo)
+
+	t.Tx.Txid, err = common.MakeTransactionID(t.Tx)
 	return err
 }
 
+func (t *Transaction) verifySignature(publicKey string, sign []byte) (*pb.SignatureInfo, error) {
+	if t.Tx == nil {
+		return nil, errors.New("transaction can not be nil")
+	}
+	if err := t.makeDigestHash(); err != nil {
+		return nil, err
+	}
+
+	cryptoClient := crypto.GetCryptoClient()
+	ecdsaPublicKey, err := cryptoClient.GetEcdsaPublicKeyFromJsonStr(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	address, err := cryptoClient.GetAddressFromPublicKey(ecdsaPublicKey)
+	if err != nil {
+		return nil, err
+	}
+	if !inSlice(t.Tx.AuthRequire, address) {
+		return nil, errors.New("this account not in transaction's AuthRequire list")
+	}
+
+	ok, err := cryptoClient.VerifyECDSA(ecdsaPublicKey, sign, t.DigestHash)
+	if err != nil || !ok {
+		return nil, common.ErrInvalidSignature
+	}
+
+	return &pb.SignatureInfo{
+		PublicKey: publicKey,
+		Sign:      sign,
+	}, nil
+}
+
+func (t *Transaction) makeDigestHash() error {
+	if t.DigestHash != nil {
+		return nil
+	}
+	digestHash, err := common.MakeTxDigestHash(t.Tx)
+	if err != nil {
+		return err
+	}
+	t.DigestHash = digestHash
+	return nil
+}
+
+// authRequireIndex index of the first AuthRequire of address or contract account, -1 if not found.
+func authRequireIndex(authRequire []string, address string) int {
+	for i, v := range authRequire {
+		if v == address || strings.HasPrefix(v, address+"/") {
+			return i
+		}
+	}
+	return -1
+}
+
 func inSlice(slice []string, str string) bool {
 	for _, v := range slice {
 		if v == str {
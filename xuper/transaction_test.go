@@ -1,9 +1,14 @@
 package xuper
 
 import (
+	"bytes"
+	"errors"
 	"testing"
 
 	"github.com/superconsensus/matrix-sdk-go/v2/account"
+	"github.com/superconsensus/matrix-sdk-go/v2/common"
+	"github.com/superconsensus/matrix-sdk-go/v2/common/config"
+	"github.com/superconsensus/matrix-sdk-go/v2/crypto"
 	"github.com/xuperchain/xuperchain/service/pb"
 )
 
@@ -64,3 +69,79 @@ func TestTransaction(t *testing.T) {
 	}
 
 }
+
+func TestWatchOnlyTransaction(t *testing.T) {
+	xc := &XClient{
+		xc: &MockXClient{},
+		cfg: &config.CommConfig{
+			ComplianceCheck: config.ComplianceCheckConfig{
+				IsNeedComplianceCheck: false,
+			},
+		},
+	}
+
+	acc, _ := account.CreateAccount(1, 1)
+	watchAcc, err := account.NewWatchOnlyAccount(acc.Address, acc.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, _ := account.CreateAccount(1, 1)
+
+	if _, err := xc.Transfer(watchAcc, other.Address, "10"); !errors.Is(err, common.ErrWatchOnlyAccount) {
+		t.Error("Watch only transfer without WithNotPost assert failed", err)
+	}
+	req, err := NewTransferRequest(acc, other.Address, "10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.SetInitiatorAccount(nil)
+	if _, err := xc.Do(req); !errors.Is(err, common.ErrInvalidInitiator) {
+		t.Error("Do nil initiator assert failed", err)
+	}
+
+	tx, err := xc.Transfer(watchAcc, other.Address, "10", WithNotPost(), WithOtherAuthRequires([]string{other.Address}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tx.Tx.InitiatorSigns) != 0 || len(tx.DigestHash) == 0 {
+		t.Fatal("Watch only transaction should not be signed")
+	}
+	if _, err := xc.PostTx(tx); err == nil {
+		t.Error("Post unsigned transaction assert failed")
+	}
+	if err := tx.Sign(watchAcc); !errors.Is(err, common.ErrWatchOnlyAccount) {
+		t.Error("Watch only account sign assert failed", err)
+	}
+
+	// external signer, such as HSM.
+	cryptoClient := crypto.GetCryptoClient()
+	privateKey, _ := cryptoClient.GetEcdsaPrivateKeyFromJsonStr(acc.PrivateKey)
+	sign, _ := cryptoClient.SignECDSA(privateKey, tx.DigestHash)
+
+	if err := tx.AddInitiatorSignature(acc.PublicKey, []byte("bad sign")); !errors.Is(err, common.ErrInvalidSignature) {
+		t.Error("Add invalid initiator signature assert failed", err)
+	}
+	if err := tx.AddInitiatorSignature(other.PublicKey, sign); err == nil {
+		t.Error("Add initiator signature with wrong public key assert failed")
+	}
+	if err := tx.AddInitiatorSignature(acc.PublicKey, sign); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.AddInitiatorSignature(acc.PublicKey, sign); err == nil {
+		t.Error("Add initiator signature twice assert failed")
+	}
+
+	otherKey, _ := cryptoClient.GetEcdsaPrivateKeyFromJsonStr(other.PrivateKey)
+	otherSign, _ := cryptoClient.SignECDSA(otherKey, tx.DigestHash)
+	if err := tx.AddSignature(other.PublicKey, otherSign); err != nil {
+		t.Fatal(err)
+	}
+
+	txid, _ := common.MakeTransactionID(tx.Tx)
+	if len(tx.Tx.AuthRequireSigns) != 2 || !bytes.Equal(txid, tx.Tx.Txid) {
+		t.Error("Watch only transaction signatures assert failed")
+	}
+	if _, err := xc.PostTx(tx); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,219 @@
+package xuper
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+	"sort"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/xuperchain/xuperchain/service/pb"
+
+	"github.com/superconsensus/matrix-sdk-go/v2/account"
+	"github.com/superconsensus/matrix-sdk-go/v2/common"
+)
+
+const defaultUTXOCount = 100
+
+// UTXO unspent output of an address.
+type UTXO struct {
+	RefTxid   string
+	RefOffset int32
+	Amount    *big.Int
+}
+
+// UTXORecord UTXOs of the same state.
+type UTXORecord struct {
+	// Count and Amount of all UTXOs of the state, UTXOs may be fewer because of the display count.
+	Count  int64
+	Amount *big.Int
+	UTXOs  []*UTXO
+}
+
+// UTXOList UTXOs of an address grouped by state.
+type UTXOList struct {
+	Address string
+	Open    *UTXORecord
+	// Locked selected by unconfirmed transactions.
+	Locked *UTXORecord
+	// Frozen not reaching the frozen height.
+	Frozen *UTXORecord
+}
+
+// ListUTXOs list UTXOs of the address, see WithQueryUTXOCount.
+//
+// Parameters:
+//   - `address`: AK address or contract account.
+func (x *XClient) ListUTXOs(address string, opts ...QueryOption) (*UTXOList, error) {
+	if address == "" {
+		return nil, errors.New("address can not be empty")
+	}
+	opt, err := initQueryOpts(opts...)
+	if err != nil {
+		return nil, err
+	}
+	count := opt.utxoCount
+	if count == 0 {
+		count = defaultUTXOCount
+	}
+
+	in := &pb.UtxoRecordDetail{
+		Bcname:       getBCname(opt),
+		AccountName:  address,
+		DisplayCount: count,
+	}
+	detail, err := x.xc.QueryUtxoRecord(context.TODO(), in)
+	if err != nil {
+		return nil, err
+	}
+	if detail == nil {
+		return nil, errors.New("empty UTXO record response")
+	}
+	if detail.GetHeader().GetError() != pb.XChainErrorEnum_SUCCESS {
+		return nil, errors.New(detail.GetHeader().GetError().String())
+	}
+
+	list := &UTXOList{Address: address}
+	if list.Open, err = newUTXORecord(detail.GetOpenUtxoRecord()); err != nil {
+		return nil, err
+	}
+	if list.Locked, err = newUTXORecord(detail.GetLockedUtxoRecord()); err != nil {
+		return nil, err
+	}
+	if list.Frozen, err = newUTXORecord(detail.GetFrozenUtxoRecord()); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func newUTXORecord(record *pb.UtxoRecord) (*UTXORecord, error) {
+	r := &UTXORecord{Amount: big.NewInt(0), UTXOs: []*UTXO{}}
+	if record == nil {
+		return r, nil
+	}
+	var err error
+	if record.GetUtxoCount() != "" {
+		if r.Count, err = strconv.ParseInt(record.GetUtxoCount(), 10, 64); err != nil {
+			return nil, errors.Wrap(err, "invalid UTXO count")
+		}
+	}
+	if record.GetUtxoAmount() != "" {
+		if _, ok := r.Amount.SetString(record.GetUtxoAmount(), 10); !ok {
+			return nil, common.ErrInvalidAmount
+		}
+	}
+	for _, item := range record.GetItem() {
+		offset, err := strconv.ParseInt(item.GetOffset(), 10, 32)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid UTXO offset")
+		}
+		amount, ok := new(big.Int).SetString(item.GetAmount(), 10)
+		if !ok {
+			return nil, common.ErrInvalidAmount
+		}
+		r.UTXOs = append(r.UTXOs, &UTXO{RefTxid: item.GetRefTxid(), RefOffset: int32(offset), Amount: amount})
+	}
+	return r, nil
+}
+
+// ConsolidateUTXOs merge small UTXOs of the account into one output per tx, by as many self transfers as needed.
+// The node selects mergeable UTXOs by the tx size limit, the smallest ones are merged first.
+// It stops when fewer than 2 UTXOs can be merged or the merged amount does not cover the fee,
+// with WithNotPost the UTXOs are not spent so only the txs of different UTXOs are built.
+//
+// Parameters:
+//   - `from`          : The contract account UTXOs are merged if it is set, otherwise the AK ones.
+//   - `maxInputsPerTx`: Max inputs of a tx, 0 means the node limit only.
+//   - `opts`          : Such as WithFee, WithBcname and WithNotPost.
+func (x *XClient) ConsolidateUTXOs(from *account.Account, maxInputsPerTx int, opts ...RequestOption) ([]*Transaction, error) {
+	if maxInputsPerTx < 0 || maxInputsPerTx == 1 {
+		return nil, errors.Wrap(common.ErrInvalidParam, "max inputs per tx must be 0 or greater than 1")
+	}
+	req, err := NewRequest(from, "", "", "", nil, "", "", opts...)
+	if err != nil {
+		return nil, err
+	}
+	if req.opt.onlyFeeFromAccount || req.opt.feePayer != nil {
+		return nil, errors.Wrap(common.ErrInvalidParam, "consolidation fee must be paid by the merged UTXOs")
+	}
+	if from.IsWatchOnly() && !req.opt.notPost {
+		return nil, errors.Wrap(common.ErrWatchOnlyAccount, "watch only initiator must use WithNotPost")
+	}
+	if x.cfg.ComplianceCheck.IsNeedComplianceCheck {
+		return nil, errors.New("consolidation is not supported with compliance check")
+	}
+	fee := big.NewInt(0)
+	if req.opt.fee != "" {
+		if _, ok := fee.SetString(req.opt.fee, 10); !ok {
+			return nil, common.ErrInvalidAmount
+		}
+	}
+
+	proposal, err := NewProposal(x, req, x.cfg)
+	if err != nil {
+		return nil, err
+	}
+	address := proposal.getInitiator()
+
+	// outputs of consolidation txs and UTXOs already merged, they are never selected again.
+	used := map[string]bool{}
+	txs := []*Transaction{}
+	for {
+		out, err := x.xc.SelectUTXOBySize(context.TODO(), &pb.UtxoInput{
+			Bcname:  proposal.getChainName(),
+			Address: address,
+		})
+		if err != nil {
+			return txs, errors.Wrap(err, "SelectUTXOBySize failed")
+		}
+		if out.GetHeader().GetError() != pb.XChainErrorEnum_SUCCESS {
+			return txs, errors.New(out.GetHeader().GetError().String())
+		}
+
+		utxos := []*pb.Utxo{}
+		for _, utxo := range out.GetUtxoList() {
+			txid := hex.EncodeToString(utxo.GetRefTxid())
+			if !used[txid] && !used[utxoKey(utxo.GetRefTxid(), utxo.GetRefOffset())] {
+				utxos = append(utxos, utxo)
+			}
+		}
+		sort.SliceStable(utxos, func(i, j int) bool {
+			return new(big.Int).SetBytes(utxos[i].GetAmount()).Cmp(new(big.Int).SetBytes(utxos[j].GetAmount())) < 0
+		})
+		if maxInputsPerTx > 0 && len(utxos) > maxInputsPerTx {
+			utxos = utxos[:maxInputsPerTx]
+		}
+		total := big.NewInt(0)
+		for _, utxo := range utxos {
+			total.Add(total, new(big.Int).SetBytes(utxo.GetAmount()))
+		}
+		if len(utxos) < 2 || total.Cmp(fee) <= 0 {
+			return txs, nil
+		}
+
+		proposal.preResp = &pb.PreExecWithSelectUTXOResponse{
+			Response:   &pb.InvokeResponse{},
+			UtxoOutput: &pb.UtxoOutput{UtxoList: utxos, TotalSelected: total.String()},
+		}
+		tx, err := proposal.GenCompleteTx()
+		if err != nil {
+			return txs, err
+		}
+		if !req.opt.notPost {
+			if tx, err = x.PostTx(tx); err != nil {
+				return txs, err
+			}
+		}
+		txs = append(txs, tx)
+
+		used[hex.EncodeToString(tx.Tx.GetTxid())] = true
+		for _, utxo := range utxos {
+			used[utxoKey(utxo.GetRefTxid(), utxo.GetRefOffset())] = true
+		}
+	}
+}
+
+func utxoKey(refTxid []byte, offset int32) string {
+	return hex.EncodeToString(refTxid) + "_" + strconv.Itoa(int(offset))
+}
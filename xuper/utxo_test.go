@@ -0,0 +1,137 @@
+package xuper
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/xuperchain/xuperchain/service/pb"
+	"google.golang.org/grpc"
+
+	"github.com/superconsensus/matrix-sdk-go/v2/account"
+	"github.com/superconsensus/matrix-sdk-go/v2/common/config"
+)
+
+// mockUTXOXClient mock node with UTXOs of one address, posted txs spend and create UTXOs,
+// SelectUTXOBySize returns at most 5 UTXOs.
+type mockUTXOXClient struct {
+	*MockXClient
+	utxos  []*pb.Utxo
+	posted []*pb.Transaction
+}
+
+func (m *mockUTXOXClient) SelectUTXOBySize(ctx context.Context, in *pb.UtxoInput, opts ...grpc.CallOption) (*pb.UtxoOutput, error) {
+	out := &pb.UtxoOutput{Header: newHeader()}
+	for _, utxo := range m.utxos {
+		if string(utxo.ToAddr) == in.GetAddress() && len(out.UtxoList) < 5 {
+			out.UtxoList = append(out.UtxoList, utxo)
+		}
+	}
+	return out, nil
+}
+
+func (m *mockUTXOXClient) PostTx(ctx context.Context, in *pb.TxStatus, opts ...grpc.CallOption) (*pb.CommonReply, error) {
+	tx := in.GetTx()
+	m.posted = append(m.posted, tx)
+	spent := map[string]bool{}
+	for _, input := range tx.GetTxInputs() {
+		spent[utxoKey(input.GetRefTxid(), input.GetRefOffset())] = true
+	}
+	utxos := []*pb.Utxo{}
+	for _, utxo := range m.utxos {
+		if !spent[utxoKey(utxo.GetRefTxid(), utxo.GetRefOffset())] {
+			utxos = append(utxos, utxo)
+		}
+	}
+	for i, output := range tx.GetTxOutputs() {
+		utxos = append(utxos, &pb.Utxo{RefTxid: tx.GetTxid(), RefOffset: int32(i), ToAddr: output.GetToAddr(), Amount: output.GetAmount()})
+	}
+	m.utxos = utxos
+	return &pb.CommonReply{Header: newHeader()}, nil
+}
+
+func (m *mockUTXOXClient) QueryUtxoRecord(ctx context.Context, in *pb.UtxoRecordDetail, opts ...grpc.CallOption) (*pb.UtxoRecordDetail, error) {
+	return &pb.UtxoRecordDetail{
+		Header: newHeader(),
+		OpenUtxoRecord: &pb.UtxoRecord{
+			UtxoCount:  "2",
+			UtxoAmount: "30",
+			Item:       []*pb.UtxoKey{{RefTxid: "0a", Offset: "1", Amount: "10"}},
+		},
+		FrozenUtxoRecord: &pb.UtxoRecord{UtxoCount: "0", UtxoAmount: "0"},
+	}, nil
+}
+
+func TestListUTXOs(t *testing.T) {
+	xc := &XClient{xc: &mockUTXOXClient{MockXClient: &MockXClient{}}}
+	if _, err := xc.ListUTXOs("alice", WithQueryUTXOCount(0)); err == nil {
+		t.Error("ListUTXOs invalid count assert failed")
+	}
+	list, err := xc.ListUTXOs("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.Open.Count != 2 || list.Open.Amount.Int64() != 30 || len(list.Open.UTXOs) != 1 {
+		t.Fatalf("ListUTXOs open record assert failed: %+v", list.Open)
+	}
+	if u := list.Open.UTXOs[0]; u.RefTxid != "0a" || u.RefOffset != 1 || u.Amount.Int64() != 10 {
+		t.Errorf("ListUTXOs utxo assert failed: %+v", u)
+	}
+	if list.Locked.Count != 0 || list.Frozen.Amount.Sign() != 0 {
+		t.Error("ListUTXOs empty records assert failed")
+	}
+}
+
+func TestConsolidateUTXOs(t *testing.T) {
+	acc, _ := account.CreateAccount(1, 1)
+	mock := &mockUTXOXClient{MockXClient: &MockXClient{}}
+	for i := 0; i < 12; i++ {
+		mock.utxos = append(mock.utxos, &pb.Utxo{
+			RefTxid: []byte{byte(i)},
+			ToAddr:  []byte(acc.Address),
+			Amount:  big.NewInt(int64(12 - i)).Bytes(),
+		})
+	}
+	xc := &XClient{xc: mock, cfg: &config.CommConfig{TxVersion: 1}}
+
+	if _, err := xc.ConsolidateUTXOs(acc, 1); err == nil {
+		t.Error("ConsolidateUTXOs max inputs 1 assert failed")
+	}
+
+	txs, err := xc.ConsolidateUTXOs(acc, 4, WithFee("1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txs) == 0 || len(txs) != len(mock.posted) {
+		t.Fatal("ConsolidateUTXOs txs count assert failed", len(txs), len(mock.posted))
+	}
+	for _, tx := range txs {
+		if n := len(tx.Tx.GetTxInputs()); n < 2 || n > 4 {
+			t.Error("ConsolidateUTXOs inputs count assert failed", n)
+		}
+		if len(tx.Tx.GetTxid()) == 0 || len(tx.Tx.GetInitiatorSigns()) != 1 {
+			t.Error("ConsolidateUTXOs signed tx assert failed", hex.EncodeToString(tx.Tx.GetTxid()))
+		}
+	}
+
+	// total amount minus fees stays with the address, consolidation outputs are not merged again.
+	total := int64(0)
+	for _, utxo := range mock.utxos {
+		if string(utxo.ToAddr) == acc.Address {
+			total += new(big.Int).SetBytes(utxo.Amount).Int64()
+		}
+	}
+	if total != 78-int64(len(txs)) {
+		t.Error("ConsolidateUTXOs total amount assert failed", total, len(txs))
+	}
+	if len(mock.utxos) >= 12 {
+		t.Error("ConsolidateUTXOs UTXOs count assert failed", len(mock.utxos))
+	}
+
+	// the smallest UTXOs are merged first.
+	first := txs[0].Tx.GetTxInputs()
+	if new(big.Int).SetBytes(first[0].GetAmount()).Int64() > new(big.Int).SetBytes(first[len(first)-1].GetAmount()).Int64() {
+		t.Error("ConsolidateUTXOs inputs order assert failed")
+	}
+}
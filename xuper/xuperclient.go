@@ -310,6 +310,20 @@ func (x *XClient) InvokeEVMContract(from *account.Account, name, method string,
 	return x.Do(req)
 }
 
+// InvokeContracts invoke several contracts in one transaction, see NewMultiInvokeRequest.
+//
+// Parameters:
+//   - `from`   : Transaction initiator.
+//   - `invokes`: Built by NewInvokeContractRequest, NewDeployContractRequest and so on.
+func (x *XClient) InvokeContracts(from *account.Account, invokes []*Request, opts ...RequestOption) (*Transaction, error) {
+	req, err := NewMultiInvokeRequest(from, invokes, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return x.Do(req)
+}
+
 // QueryWasmContract query wasm c++ contract.
 //
 // Parameters:
@@ -423,6 +437,20 @@ func (x *XClient) SetMethodACL(from *account.Account, name, method string, acl *
 
 // Do generete tx & post tx.
 func (x *XClient) Do(req *Request) (*Transaction, error) {
+	if req == nil {
+		return nil, errors.New("request can not be nil")
+	}
+	if req.initiatorAccount == nil {
+		return nil, common.ErrInvalidInitiator
+	}
+	// watch only initiator can not sign, the tx must be signed and posted by caller.
+	if req.initiatorAccount.IsWatchOnly() && !req.opt.notPost {
+		return nil, errors.Wrap(common.ErrWatchOnlyAccount, "watch only initiator must use WithNotPost")
+	}
+	if payer := req.opt.feePayer; payer != nil && payer.IsWatchOnly() && !req.opt.notPost {
+		return nil, errors.Wrap(common.ErrWatchOnlyAccount, "watch only fee payer must use WithNotPost")
+	}
+
 	transaction, err := x.GenerateTx(req)
 	if err != nil {
 		return nil, err
@@ -463,17 +491,35 @@ func (x *XClient) PreExecTx(req *Request) (*Transaction, error) {
 	}
 
 	return &Transaction{
-		ContractResponse: cr,
+		ContractResponse:  cr,
+		ContractResponses: proposal.invokeResponses(),
 	}, nil
 }
 
 // PostTx post tx to node.
 func (x *XClient) PostTx(tx *Transaction) (*Transaction, error) {
+	if len(tx.Tx.GetInitiatorSigns()) == 0 {
+		return nil, errors.New("transaction has no initiator signature")
+	}
+	if x.opt != nil && x.opt.authRequireCheck {
+		report, err := x.CheckAuthRequire(tx)
+		if err != nil {
+			return nil, err
+		}
+		if err := report.Err(); err != nil {
+			return nil, err
+		}
+	}
 	return tx, x.postTx(tx.Tx, tx.Bcname)
 }
 
 // WatchBlockEvent new watcher for block event.
 func (x *XClient) WatchBlockEvent(opts ...BlockEventOption) (*Watcher, error) {
+	return x.watchBlockEvent(context.TODO(), opts...)
+}
+
+// watchBlockEvent the stream is canceled when ctx done.
+func (x *XClient) watchBlockEvent(ctx context.Context, opts ...BlockEventOption) (*Watcher, error) {
 	watcher, err := x.newWatcher(opts...)
 	if err != nil {
 		return nil, err
@@ -484,7 +530,7 @@ func (x *XClient) WatchBlockEvent(opts ...BlockEventOption) (*Watcher, error) {
 		Filter: buf,
 	}
 
-	stream, err := x.esc.Subscribe(context.TODO(), request)
+	stream, err := x.esc.Subscribe(ctx, request)
 	if err != nil {
 		return nil, err
 	}
@@ -514,12 +560,14 @@ func (x *XClient) WatchBlockEvent(opts ...BlockEventOption) (*Watcher, error) {
 				}
 				if err != nil {
 					log.Printf("Get block event err: %v", err)
+					watcher.err = err
 					return
 				}
 				var block pb.FilteredBlock
 				err = proto.Unmarshal(event.Payload, &block)
 				if err != nil {
 					log.Printf("Get block event err: %v", err)
+					watcher.err = err
 					return
 				}
 				if len(block.GetTxs()) == 0 && watcher.opt.skipEmptyTx {
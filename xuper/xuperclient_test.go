@@ -737,7 +737,7 @@ func TestACLSet(t *testing.T) {
 			from:   aks[1],
 			name:   "hello",
 			method: "a",
-			acl:    getDefaultACL("a"),
+			acl:    getDefaultACL(aks[0].Address),
 			cfg: &config.CommConfig{
 				ComplianceCheck: config.ComplianceCheckConfig{},
 			},
@@ -746,7 +746,7 @@ func TestACLSet(t *testing.T) {
 		},
 		{
 			from: aks[1],
-			acl:  getDefaultACL("a"),
+			acl:  getDefaultACL(aks[0].Address),
 			cfg: &config.CommConfig{
 				ComplianceCheck: config.ComplianceCheckConfig{},
 			},
@@ -757,7 +757,7 @@ func TestACLSet(t *testing.T) {
 			from:   aks[1],
 			name:   "hello",
 			method: "a",
-			acl:    getDefaultACL("a"),
+			acl:    getDefaultACL(aks[0].Address),
 			cfg: &config.CommConfig{
 				ComplianceCheck: config.ComplianceCheckConfig{},
 			},
@@ -769,7 +769,7 @@ func TestACLSet(t *testing.T) {
 		},
 		{
 			from: aks[1],
-			acl:  getDefaultACL("a"),
+			acl:  getDefaultACL(aks[0].Address),
 			cfg: &config.CommConfig{
 				ComplianceCheck: config.ComplianceCheckConfig{
 					IsNeedComplianceCheck: true,
@@ -782,7 +782,7 @@ func TestACLSet(t *testing.T) {
 			from:   aks[1],
 			name:   "hello",
 			method: "a",
-			acl:    getDefaultACL("a"),
+			acl:    getDefaultACL(aks[0].Address),
 			opts:   []RequestOption{},
 			cfg: &config.CommConfig{
 				ComplianceCheck: config.ComplianceCheckConfig{
@@ -800,7 +800,7 @@ func TestACLSet(t *testing.T) {
 			from:   aks[1],
 			name:   "hello",
 			method: "a",
-			acl:    getDefaultACL("a"),
+			acl:    getDefaultACL(aks[0].Address),
 			opts:   []RequestOption{WithFeeFromAccount(), WithFee("10")},
 			cfg: &config.CommConfig{
 				ComplianceCheck: config.ComplianceCheckConfig{